@@ -0,0 +1,155 @@
+package dvbcrud
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// AggregateFunc denotes an SQL aggregate function.
+type AggregateFunc int
+
+const (
+	// AggregateCount is COUNT(column), or COUNT(*) without a column
+	AggregateCount AggregateFunc = iota
+	// AggregateSum is SUM(column)
+	AggregateSum
+	// AggregateMin is MIN(column)
+	AggregateMin
+	// AggregateMax is MAX(column)
+	AggregateMax
+	// AggregateAvg is AVG(column)
+	AggregateAvg
+)
+
+// aggregate aggregates column with fn over the rows matching filter and scans the result into dest.
+func (r SQLRepository[T]) aggregate(ctx context.Context, fn AggregateFunc, column string, filter Filter, dest any) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := templates.GetAggregate(fn, column, "", filter)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	return stmt.QueryRowxContext(ctx, args...).Scan(dest)
+}
+
+// Sum returns the sum of column over the rows that match filter, or 0 if no rows match.
+func (r SQLRepository[T]) Sum(column string, filter Filter) (float64, error) {
+	return r.SumContext(context.Background(), column, filter)
+}
+
+// SumContext returns the sum of column over the rows that match filter, or 0 if no rows match.
+func (r SQLRepository[T]) SumContext(ctx context.Context, column string, filter Filter) (float64, error) {
+	var sum sql.NullFloat64
+	if err := r.aggregate(ctx, AggregateSum, column, filter, &sum); err != nil {
+		return 0, err
+	}
+	return sum.Float64, nil
+}
+
+// Avg returns the average of column over the rows that match filter, or 0 if no rows match.
+func (r SQLRepository[T]) Avg(column string, filter Filter) (float64, error) {
+	return r.AvgContext(context.Background(), column, filter)
+}
+
+// AvgContext returns the average of column over the rows that match filter, or 0 if no rows match.
+// Note that SQL Server averages integer columns with integer division.
+func (r SQLRepository[T]) AvgContext(ctx context.Context, column string, filter Filter) (float64, error) {
+	var avg sql.NullFloat64
+	if err := r.aggregate(ctx, AggregateAvg, column, filter, &avg); err != nil {
+		return 0, err
+	}
+	return avg.Float64, nil
+}
+
+// Min scans the smallest value of column over the rows that match filter into dest.
+func (r SQLRepository[T]) Min(column string, filter Filter, dest any) error {
+	return r.MinContext(context.Background(), column, filter, dest)
+}
+
+// MinContext scans the smallest value of column over the rows that match filter into dest.
+// dest must be able to hold NULL, e.g. a sql.NullInt64, in case no rows match.
+func (r SQLRepository[T]) MinContext(ctx context.Context, column string, filter Filter, dest any) error {
+	return r.aggregate(ctx, AggregateMin, column, filter, dest)
+}
+
+// Max scans the largest value of column over the rows that match filter into dest.
+func (r SQLRepository[T]) Max(column string, filter Filter, dest any) error {
+	return r.MaxContext(context.Background(), column, filter, dest)
+}
+
+// MaxContext scans the largest value of column over the rows that match filter into dest.
+// dest must be able to hold NULL, e.g. a sql.NullInt64, in case no rows match.
+func (r SQLRepository[T]) MaxContext(ctx context.Context, column string, filter Filter, dest any) error {
+	return r.aggregate(ctx, AggregateMax, column, filter, dest)
+}
+
+// GroupBy aggregates column with fn over the rows in the table of repo that match filter,
+// per distinct value of groupColumn, and returns the aggregates by group.
+func GroupBy[T any, K comparable, V any](repo *SQLRepository[T], groupColumn string, fn AggregateFunc, column string, filter Filter) (map[K]V, error) {
+	return GroupByContext[T, K, V](context.Background(), repo, groupColumn, fn, column, filter)
+}
+
+// GroupByContext aggregates column with fn over the rows in the table of repo that match filter,
+// per distinct value of groupColumn, and returns the aggregates by group.
+//
+// An empty column counts the rows in each group with COUNT(*). The values of groupColumn are
+// scanned into K and the aggregates into V, so K must be able to hold NULL if groupColumn can.
+func GroupByContext[T any, K comparable, V any](ctx context.Context, repo *SQLRepository[T], groupColumn string, fn AggregateFunc, column string, filter Filter) (map[K]V, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+	if groupColumn == "" {
+		return nil, fmt.Errorf("groupColumn cannot be empty")
+	}
+
+	ctx, cancel := repo.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := repo.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := templates.GetAggregate(fn, column, groupColumn, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := repo.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryxContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[K]V{}
+	for rows.Next() {
+		var key K
+		var value V
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+
+	return result, rows.Err()
+}
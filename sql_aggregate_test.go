@@ -0,0 +1,107 @@
+package dvbcrud
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+func newAggregateMock(t *testing.T, expectedFn AggregateFunc, expectedGroupBy string) sqlTemplatesMock {
+	return sqlTemplatesMock{
+		GetAggregateMock: func(fn AggregateFunc, column string, groupBy string, filter Filter) (string, []any, error) {
+			if fn != expectedFn || groupBy != expectedGroupBy {
+				t.Fatalf("Expected aggregate %d grouped by \"%s\" but got %d grouped by \"%s\"", expectedFn, expectedGroupBy, fn, groupBy)
+			}
+			return "AnyAggregate", []any{"AnyName"}, nil
+		},
+	}
+}
+
+func TestSqlRepository_Sum(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = newAggregateMock(t, AggregateSum, "")
+
+	mock.ExpectPrepare("AnyAggregate").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"SUM(UserId)"}).AddRow("12.50"))
+
+	actual, err := repo.Sum("UserId", Eq("Name", "AnyName"))
+	if err != nil || actual != 12.5 {
+		t.Fatalf("Expected 12.5 but got %v (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_Avg_NoRows(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = newAggregateMock(t, AggregateAvg, "")
+
+	mock.ExpectPrepare("AnyAggregate").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"AVG(UserId)"}).AddRow(nil))
+
+	actual, err := repo.Avg("UserId", Eq("Name", "AnyName"))
+	if err != nil || actual != 0 {
+		t.Fatalf("Expected 0 but got %v (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_Max(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = newAggregateMock(t, AggregateMax, "")
+
+	mock.ExpectPrepare("AnyAggregate").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"MAX(Name)"}).AddRow("Zed"))
+
+	var actual sql.NullString
+	err := repo.Max("Name", Eq("Name", "AnyName"), &actual)
+	if err != nil || actual.String != "Zed" {
+		t.Fatalf("Expected Zed but got %v (%v)", actual, err)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = newAggregateMock(t, AggregateCount, "Surname")
+
+	mock.ExpectPrepare("AnyAggregate").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"Surname", "COUNT(*)"}).
+			AddRow("Doe", 3).
+			AddRow("Roe", 1)).
+		RowsWillBeClosed()
+
+	actual, err := GroupBy[repoTestUser, string, int64](repo, "Surname", AggregateCount, "", Eq("Name", "AnyName"))
+	if err != nil {
+		t.Fatalf("Expected GroupBy to succeed, but got: %s", err)
+	}
+
+	expected := map[string]int64{"Doe": 3, "Roe": 1}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Expected rows to be closed, but got: %s", err)
+	}
+}
+
+func TestGroupBy_EmptyGroupColumn(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "groupColumn cannot be empty"
+
+	_, actual := GroupBy[repoTestUser, string, int64](repo, "", AggregateCount, "", nil)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
@@ -0,0 +1,118 @@
+package dvbcrud
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a cursor is malformed, has been tampered with,
+// or was created for a different order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorPayload is the signed content of a cursor.
+type cursorPayload struct {
+	OrderBy []string          `json:"o"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+// cursorCodec encodes the sort keys of a row into an opaque cursor, signed with an HMAC
+// so that clients cannot forge or alter it.
+type cursorCodec struct {
+	key []byte
+}
+
+// orderKeys returns orderBy as strings, with descending columns prefixed by "-".
+func orderKeys(orderBy []OrderBy) []string {
+	keys := make([]string, len(orderBy))
+	for i, order := range orderBy {
+		keys[i] = order.Column
+		if order.Descending {
+			keys[i] = "-" + order.Column
+		}
+	}
+	return keys
+}
+
+func (c cursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Encode returns a cursor holding values, the sort keys of a row sorted by orderBy.
+func (c cursorCodec) Encode(orderBy []OrderBy, values []any) (string, error) {
+	payload := cursorPayload{
+		OrderBy: orderKeys(orderBy),
+		Values:  make([]json.RawMessage, len(values)),
+	}
+	for i, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		payload.Values[i] = raw
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(data)), nil
+}
+
+// Decode verifies cursor and returns the sort keys it holds, converted to types.
+// cursor must have been encoded with the same key and orderBy.
+func (c cursorCodec) Decode(cursor string, orderBy []OrderBy, types []reflect.Type) ([]any, error) {
+	encodedData, encodedSignature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encodedData)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !hmac.Equal(signature, c.sign(data)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !reflect.DeepEqual(payload.OrderBy, orderKeys(orderBy)) || len(payload.Values) != len(types) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, len(types))
+	for i, typ := range types {
+		value := reflect.New(typ)
+		if err := json.Unmarshal(payload.Values[i], value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	return values, nil
+}
+
+// newCursorKey returns a random key for signing cursors.
+func newCursorKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,62 @@
+package dvbcrud
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var cursorTestTypes = []reflect.Type{reflect.TypeOf(time.Time{}), reflect.TypeOf(uint64(0))}
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := cursorCodec{key: []byte("AnySecret")}
+	orderBy := []OrderBy{Desc("CreatedAt"), Asc("UserId")}
+	expected := []any{time.Date(2026, 10, 17, 12, 0, 0, 1, time.UTC), uint64(1<<63 + 1)}
+
+	cursor, err := codec.Encode(orderBy, expected)
+	if err != nil {
+		t.Fatalf("Expected cursor, but got: %s", err)
+	}
+
+	actual, err := codec.Decode(cursor, orderBy, cursorTestTypes)
+	if err != nil {
+		t.Fatalf("Expected values, but got: %s", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestCursorCodec_Invalid(t *testing.T) {
+	codec := cursorCodec{key: []byte("AnySecret")}
+	orderBy := []OrderBy{Desc("CreatedAt"), Asc("UserId")}
+	cursor, _ := codec.Encode(orderBy, []any{time.Now(), uint64(1)})
+	forged, _ := cursorCodec{key: []byte("OtherSecret")}.Encode(orderBy, []any{time.Now(), uint64(1)})
+
+	tests := map[string]string{
+		"malformed":    "AnyCursor",
+		"bad encoding": "!!!.!!!",
+		"tampered":     "x" + cursor,
+		"forged":       forged,
+		"empty":        ".",
+		"altered":      cursor[:len(cursor)-2] + "AA",
+	}
+
+	for name, cursor := range tests {
+		_, actual := codec.Decode(cursor, orderBy, cursorTestTypes)
+		if actual != ErrInvalidCursor {
+			t.Fatalf("Expected %v for %s cursor but got %v", ErrInvalidCursor, name, actual)
+		}
+	}
+}
+
+func TestCursorCodec_DifferentOrder(t *testing.T) {
+	codec := cursorCodec{key: []byte("AnySecret")}
+	cursor, _ := codec.Encode([]OrderBy{Desc("CreatedAt"), Asc("UserId")}, []any{time.Now(), uint64(1)})
+
+	_, actual := codec.Decode(cursor, []OrderBy{Asc("CreatedAt"), Asc("UserId")}, cursorTestTypes)
+
+	if actual != ErrInvalidCursor {
+		t.Fatalf("Expected %v but got %v", ErrInvalidCursor, actual)
+	}
+}
@@ -1,13 +1,237 @@
 package dvbcrud
 
-// SQLDialect denotes the different dialects which define placeholders differently.
-type SQLDialect int
+import (
+	"fmt"
+	"sync"
+)
+
+// Dialect describes how statements are written for a specific database.
+//
+// Custom dialects can be created by implementing Dialect, or by embedding one of
+// the built-in dialects and overriding the methods that differ.
+type Dialect interface {
+	// Name returns the name that the dialect is registered under.
+	Name() string
+
+	// Placeholders returns amount parameter placeholders, numbered from offset+1.
+	// offset is the number of parameters that precede them in the statement.
+	Placeholders(offset int, amount int, typ ParameterType) []string
+
+	// QuoteIdentifier quotes a single, unqualified identifier.
+	QuoteIdentifier(identifier string) string
+
+	// Pagination returns the syntax used for limiting and offsetting results.
+	Pagination() PaginationStyle
+
+	// Returning returns how generated values are read back after an INSERT.
+	Returning() ReturningStyle
+
+	// Upsert returns the syntax used for inserting or updating a row in one statement.
+	Upsert() UpsertStyle
+
+	// Savepoints returns the syntax used for savepoints in nested transactions.
+	Savepoints() SavepointStyle
+
+	// MultiRowInsert returns the syntax used for inserting several rows in one statement.
+	MultiRowInsert() MultiRowInsertStyle
+
+	// BatchLimits returns how many parameters and rows a single statement may hold.
+	BatchLimits() BatchLimits
+
+	// RowValueComparison reports whether row values can be compared, as in (a, b) > (?, ?).
+	RowValueComparison() bool
+
+	// Locking returns the syntax used for locking the rows read by a SELECT.
+	Locking() LockingStyle
+
+	// IdentifierCase returns how the database folds the case of unquoted identifiers.
+	IdentifierCase() IdentifierCaseStyle
+}
+
+// PaginationStyle denotes the syntax a Dialect uses for pagination.
+type PaginationStyle int
 
 const (
-	MySQL SQLDialect = iota
-	PostgreSQL
-	Oracle
-	SQLite
-	ODBC
-	MariaDB
+	PaginationUnsupported PaginationStyle = iota
+	// PaginationLimitOffset is LIMIT n OFFSET m
+	PaginationLimitOffset
+	// PaginationOffsetFetch is OFFSET m ROWS FETCH NEXT n ROWS ONLY
+	PaginationOffsetFetch
+	// PaginationRowNum wraps the query in subqueries filtering on ROWNUM, for Oracle before 12c
+	PaginationRowNum
 )
+
+// ReturningStyle denotes how a Dialect reads back generated values from an INSERT.
+type ReturningStyle int
+
+const (
+	ReturningUnsupported ReturningStyle = iota
+	// ReturningLastInsertID uses sql.Result.LastInsertId
+	ReturningLastInsertID
+	// ReturningClause is INSERT ... RETURNING columns
+	ReturningClause
+	// ReturningInto is INSERT ... RETURNING column INTO :out
+	ReturningInto
+	// ReturningOutput is INSERT ... OUTPUT INSERTED.columns VALUES ...
+	ReturningOutput
+)
+
+// UpsertStyle denotes the syntax a Dialect uses for upserts.
+type UpsertStyle int
+
+const (
+	UpsertUnsupported UpsertStyle = iota
+	// UpsertOnConflict is INSERT ... ON CONFLICT (columns) DO UPDATE SET ...
+	UpsertOnConflict
+	// UpsertOnDuplicateKey is INSERT ... ON DUPLICATE KEY UPDATE ...
+	UpsertOnDuplicateKey
+	// UpsertMergeFromDual is MERGE INTO ... USING (SELECT ... FROM DUAL) ...
+	UpsertMergeFromDual
+	// UpsertMerge is MERGE INTO ... USING (SELECT ...) AS source ...;
+	UpsertMerge
+)
+
+// SavepointStyle denotes the syntax a Dialect uses for savepoints.
+type SavepointStyle int
+
+const (
+	SavepointUnsupported SavepointStyle = iota
+	// SavepointStandard is SAVEPOINT, RELEASE SAVEPOINT and ROLLBACK TO SAVEPOINT
+	SavepointStandard
+	// SavepointWithoutRelease is SAVEPOINT and ROLLBACK TO SAVEPOINT, without release
+	SavepointWithoutRelease
+	// SavepointTransaction is SAVE TRANSACTION and ROLLBACK TRANSACTION
+	SavepointTransaction
+)
+
+// MultiRowInsertStyle denotes the syntax a Dialect uses for inserting several rows at once.
+type MultiRowInsertStyle int
+
+const (
+	MultiRowInsertUnsupported MultiRowInsertStyle = iota
+	// MultiRowInsertValues is INSERT INTO ... VALUES (...), (...)
+	MultiRowInsertValues
+	// MultiRowInsertAll is INSERT ALL INTO ... VALUES (...) INTO ... VALUES (...) SELECT 1 FROM DUAL
+	MultiRowInsertAll
+)
+
+// LockingStyle denotes the syntax a Dialect uses for locking rows in a SELECT.
+type LockingStyle int
+
+const (
+	LockingUnsupported LockingStyle = iota
+	// LockingForUpdate is SELECT ... FOR UPDATE or FOR SHARE, followed by NOWAIT or SKIP LOCKED
+	LockingForUpdate
+	// LockingLockInShareMode is SELECT ... FOR UPDATE or LOCK IN SHARE MODE, followed by NOWAIT or SKIP LOCKED
+	LockingLockInShareMode
+	// LockingForUpdateOnly is SELECT ... FOR UPDATE, followed by NOWAIT or SKIP LOCKED, without shared locks
+	LockingForUpdateOnly
+	// LockingTableHints is SELECT ... FROM table WITH (UPDLOCK, ROWLOCK), or REPEATABLEREAD for shared locks
+	LockingTableHints
+)
+
+// IdentifierCaseStyle denotes how a Dialect folds the case of unquoted identifiers.
+type IdentifierCaseStyle int
+
+const (
+	// IdentifierCasePreserved keeps unquoted identifiers as they are written
+	IdentifierCasePreserved IdentifierCaseStyle = iota
+	// IdentifierCaseLower folds unquoted identifiers to lower case, so UserId becomes userid
+	IdentifierCaseLower
+	// IdentifierCaseUpper folds unquoted identifiers to upper case, so UserId becomes USERID
+	IdentifierCaseUpper
+)
+
+// BatchLimits holds the limits of a single statement. Zero means unlimited.
+type BatchLimits struct {
+	// MaxParameters is the number of bind parameters a statement may hold.
+	MaxParameters int
+	// MaxRows is the number of rows a multi-row INSERT may hold.
+	MaxRows int
+}
+
+var (
+	registryMu sync.RWMutex
+
+	// dialects maps dialect names to registered dialects.
+	dialects = map[string]Dialect{}
+
+	// driverDialects maps sqlx driver names to the dialect they speak.
+	driverDialects = map[string]Dialect{}
+)
+
+func init() {
+	builtins := map[Dialect][]string{
+		MySQL:      {"mysql", "nrmysql"},
+		PostgreSQL: {"postgres", "pgx", "pgx/v4", "pgx/v5", "pq-timeouts", "cloudsqlpostgres", "nrpostgres", "cockroach"},
+		Oracle:     {"godror", "goracle", "oci8", "ora", "oracle"},
+		SQLite:     {"sqlite3", "sqlite", "nrsqlite3"},
+		ODBC:       {"odbc"},
+		MariaDB:    {},
+		Oracle11g:  {},
+		SQLServer:  {"sqlserver", "azuresql"}, // not "mssql", whose deprecated driver expects ? placeholders
+	}
+
+	for dialect, driverNames := range builtins {
+		if err := RegisterDialect(dialect, driverNames...); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterDialect registers dialect under its name and maps each of driverNames to it,
+// so that New can detect it. Registering a name twice overwrites the previous dialect.
+func RegisterDialect(dialect Dialect, driverNames ...string) error {
+	if dialect == nil {
+		return fmt.Errorf("dialect cannot be nil")
+	}
+	if dialect.Name() == "" {
+		return fmt.Errorf("dialect name cannot be empty")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	dialects[dialect.Name()] = dialect
+	for _, driverName := range driverNames {
+		driverDialects[driverName] = dialect
+	}
+
+	return nil
+}
+
+// GetDialect returns the dialect registered under name.
+func GetDialect(name string) (Dialect, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	dialect, ok := dialects[name]
+	return dialect, ok
+}
+
+// RegisterDriver maps driverName to dialect, so that New can detect the dialect
+// of databases opened with that driver. Existing mappings are overwritten.
+func RegisterDriver(driverName string, dialect Dialect) error {
+	if dialect == nil {
+		return fmt.Errorf("dialect cannot be nil")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	driverDialects[driverName] = dialect
+
+	return nil
+}
+
+// DetectDialect returns the dialect registered for driverName.
+func DetectDialect(driverName string) (Dialect, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	dialect, ok := driverDialects[driverName]
+	if !ok {
+		return nil, fmt.Errorf("cannot detect dialect for driver \"%s\", use WithDialect or RegisterDriver", driverName)
+	}
+
+	return dialect, nil
+}
@@ -0,0 +1,261 @@
+package dvbcrud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Built-in dialects.
+var (
+	MySQL      Dialect = mysqlDialect{}
+	PostgreSQL Dialect = postgresDialect{}
+	Oracle     Dialect = oracleDialect{}
+	Oracle11g  Dialect = oracle11gDialect{}
+	SQLite     Dialect = sqliteDialect{}
+	ODBC       Dialect = odbcDialect{}
+	MariaDB    Dialect = mariaDBDialect{}
+	SQLServer  Dialect = sqlServerDialect{}
+)
+
+// questionMarks returns amount "?" placeholders.
+func questionMarks(amount int) []string {
+	placeholders := make([]string, amount)
+	for i := 0; i < amount; i++ {
+		placeholders[i] = "?"
+	}
+	return placeholders
+}
+
+// quoteWith wraps identifier in open and close, doubling any close characters inside it.
+func quoteWith(identifier string, open string, close string) string {
+	return open + strings.ReplaceAll(identifier, close, close+close) + close
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+func (mysqlDialect) Placeholders(_ int, amount int, _ ParameterType) []string {
+	return questionMarks(amount)
+}
+
+func (mysqlDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(identifier, "`", "`")
+}
+
+func (mysqlDialect) Pagination() PaginationStyle { return PaginationLimitOffset }
+
+func (mysqlDialect) Returning() ReturningStyle { return ReturningLastInsertID }
+
+func (mysqlDialect) Upsert() UpsertStyle { return UpsertOnDuplicateKey }
+
+func (mysqlDialect) Savepoints() SavepointStyle { return SavepointStandard }
+
+func (mysqlDialect) MultiRowInsert() MultiRowInsertStyle { return MultiRowInsertValues }
+
+func (mysqlDialect) BatchLimits() BatchLimits { return BatchLimits{MaxParameters: 65535} }
+
+func (mysqlDialect) RowValueComparison() bool { return true }
+
+func (mysqlDialect) Locking() LockingStyle { return LockingForUpdate }
+
+func (mysqlDialect) IdentifierCase() IdentifierCaseStyle { return IdentifierCasePreserved }
+
+type mariaDBDialect struct {
+	mysqlDialect
+}
+
+func (mariaDBDialect) Name() string { return "mariadb" }
+
+func (mariaDBDialect) Locking() LockingStyle { return LockingLockInShareMode }
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgresql" }
+
+func (postgresDialect) Placeholders(offset int, amount int, _ ParameterType) []string {
+	placeholders := make([]string, amount)
+	for i := 0; i < amount; i++ {
+		placeholders[i] = fmt.Sprintf("$%d", offset+i+1)
+	}
+	return placeholders
+}
+
+func (postgresDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(identifier, `"`, `"`)
+}
+
+func (postgresDialect) Pagination() PaginationStyle { return PaginationLimitOffset }
+
+func (postgresDialect) Returning() ReturningStyle { return ReturningClause }
+
+func (postgresDialect) Upsert() UpsertStyle { return UpsertOnConflict }
+
+func (postgresDialect) Savepoints() SavepointStyle { return SavepointStandard }
+
+func (postgresDialect) MultiRowInsert() MultiRowInsertStyle { return MultiRowInsertValues }
+
+func (postgresDialect) BatchLimits() BatchLimits { return BatchLimits{MaxParameters: 65535} }
+
+func (postgresDialect) RowValueComparison() bool { return true }
+
+func (postgresDialect) Locking() LockingStyle { return LockingForUpdate }
+
+func (postgresDialect) IdentifierCase() IdentifierCaseStyle { return IdentifierCaseLower }
+
+type oracleDialect struct{}
+
+func (oracleDialect) Name() string { return "oracle" }
+
+// Placeholders returns named placeholders, :col for Columns and :val for Values.
+// A lone placeholder at the start of a statement is left unnumbered.
+func (oracleDialect) Placeholders(offset int, amount int, typ ParameterType) []string {
+	var name string
+	if typ == Columns {
+		name = "col"
+	} else {
+		name = "val"
+	}
+
+	placeholders := make([]string, amount)
+	if offset == 0 && amount == 1 {
+		placeholders[0] = ":" + name
+		return placeholders
+	}
+	for i := 0; i < amount; i++ {
+		placeholders[i] = fmt.Sprintf(":%s%d", name, offset+i+1)
+	}
+	return placeholders
+}
+
+func (oracleDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(identifier, `"`, `"`)
+}
+
+func (oracleDialect) Pagination() PaginationStyle { return PaginationOffsetFetch }
+
+func (oracleDialect) Returning() ReturningStyle { return ReturningInto }
+
+func (oracleDialect) Upsert() UpsertStyle { return UpsertMergeFromDual }
+
+func (oracleDialect) Savepoints() SavepointStyle { return SavepointWithoutRelease }
+
+func (oracleDialect) MultiRowInsert() MultiRowInsertStyle { return MultiRowInsertAll }
+
+func (oracleDialect) BatchLimits() BatchLimits { return BatchLimits{MaxParameters: 65535} }
+
+func (oracleDialect) RowValueComparison() bool { return false }
+
+func (oracleDialect) Locking() LockingStyle { return LockingForUpdateOnly }
+
+func (oracleDialect) IdentifierCase() IdentifierCaseStyle { return IdentifierCaseUpper }
+
+// oracle11gDialect targets Oracle before 12c, which lacks OFFSET ... FETCH.
+type oracle11gDialect struct {
+	oracleDialect
+}
+
+func (oracle11gDialect) Name() string { return "oracle11g" }
+
+func (oracle11gDialect) Pagination() PaginationStyle { return PaginationRowNum }
+
+// sqliteDialect targets SQLite 3.35 or later, which supports RETURNING and 32766 parameters.
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) Placeholders(_ int, amount int, _ ParameterType) []string {
+	return questionMarks(amount)
+}
+
+func (sqliteDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(identifier, `"`, `"`)
+}
+
+func (sqliteDialect) Pagination() PaginationStyle { return PaginationLimitOffset }
+
+func (sqliteDialect) Returning() ReturningStyle { return ReturningClause }
+
+func (sqliteDialect) Upsert() UpsertStyle { return UpsertOnConflict }
+
+func (sqliteDialect) Savepoints() SavepointStyle { return SavepointStandard }
+
+func (sqliteDialect) MultiRowInsert() MultiRowInsertStyle { return MultiRowInsertValues }
+
+func (sqliteDialect) BatchLimits() BatchLimits { return BatchLimits{MaxParameters: 32766} }
+
+func (sqliteDialect) RowValueComparison() bool { return true }
+
+func (sqliteDialect) Locking() LockingStyle { return LockingUnsupported }
+
+func (sqliteDialect) IdentifierCase() IdentifierCaseStyle { return IdentifierCasePreserved }
+
+// sqlServerDialect targets Microsoft SQL Server 2012 or later.
+type sqlServerDialect struct{}
+
+func (sqlServerDialect) Name() string { return "sqlserver" }
+
+func (sqlServerDialect) Placeholders(offset int, amount int, _ ParameterType) []string {
+	placeholders := make([]string, amount)
+	for i := 0; i < amount; i++ {
+		placeholders[i] = fmt.Sprintf("@p%d", offset+i+1)
+	}
+	return placeholders
+}
+
+func (sqlServerDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(identifier, "[", "]")
+}
+
+func (sqlServerDialect) Pagination() PaginationStyle { return PaginationOffsetFetch }
+
+func (sqlServerDialect) Returning() ReturningStyle { return ReturningOutput }
+
+func (sqlServerDialect) Upsert() UpsertStyle { return UpsertMerge }
+
+func (sqlServerDialect) Savepoints() SavepointStyle { return SavepointTransaction }
+
+func (sqlServerDialect) MultiRowInsert() MultiRowInsertStyle { return MultiRowInsertValues }
+
+// BatchLimits leaves room for the @stmt and @params parameters of sp_executesql,
+// which count towards the limit of 2100 parameters.
+func (sqlServerDialect) BatchLimits() BatchLimits {
+	return BatchLimits{MaxParameters: 2098, MaxRows: 1000}
+}
+
+func (sqlServerDialect) RowValueComparison() bool { return false }
+
+func (sqlServerDialect) Locking() LockingStyle { return LockingTableHints }
+
+func (sqlServerDialect) IdentifierCase() IdentifierCaseStyle { return IdentifierCasePreserved }
+
+// odbcDialect only assumes what the ODBC standard guarantees.
+type odbcDialect struct{}
+
+func (odbcDialect) Name() string { return "odbc" }
+
+func (odbcDialect) Placeholders(_ int, amount int, _ ParameterType) []string {
+	return questionMarks(amount)
+}
+
+func (odbcDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(identifier, `"`, `"`)
+}
+
+func (odbcDialect) Pagination() PaginationStyle { return PaginationUnsupported }
+
+func (odbcDialect) Returning() ReturningStyle { return ReturningUnsupported }
+
+func (odbcDialect) Upsert() UpsertStyle { return UpsertUnsupported }
+
+func (odbcDialect) Savepoints() SavepointStyle { return SavepointUnsupported }
+
+func (odbcDialect) MultiRowInsert() MultiRowInsertStyle { return MultiRowInsertUnsupported }
+
+func (odbcDialect) BatchLimits() BatchLimits { return BatchLimits{} }
+
+func (odbcDialect) RowValueComparison() bool { return false }
+
+func (odbcDialect) Locking() LockingStyle { return LockingUnsupported }
+
+func (odbcDialect) IdentifierCase() IdentifierCaseStyle { return IdentifierCasePreserved }
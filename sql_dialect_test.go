@@ -0,0 +1,158 @@
+package dvbcrud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectDialect(t *testing.T) {
+	tests := map[string]Dialect{
+		"mysql":     MySQL,
+		"postgres":  PostgreSQL,
+		"pgx":       PostgreSQL,
+		"godror":    Oracle,
+		"sqlite3":   SQLite,
+		"odbc":      ODBC,
+		"sqlserver": SQLServer,
+		"azuresql":  SQLServer,
+	}
+
+	for driverName, expected := range tests {
+		actual, err := DetectDialect(driverName)
+		if err != nil {
+			t.Fatalf("Expected dialect for \"%s\" but got: %s", driverName, err)
+		}
+		if actual != expected {
+			t.Fatalf("Expected %s for \"%s\" but got %s", expected.Name(), driverName, actual.Name())
+		}
+	}
+}
+
+func TestDetectDialect_Unknown(t *testing.T) {
+	_, err := DetectDialect("AnyDriver")
+	if err == nil {
+		t.Fatalf("Expected error on unknown driver")
+	}
+}
+
+func TestDetectDialect_DeprecatedMssql(t *testing.T) {
+	_, err := DetectDialect("mssql")
+	if err == nil {
+		t.Fatalf("Expected the deprecated mssql driver not to be detected")
+	}
+}
+
+func TestRegisterDriver(t *testing.T) {
+	err := RegisterDriver("anydriver", MariaDB)
+	if err != nil {
+		t.Fatalf("Expected RegisterDriver to succeed, but got: %s", err)
+	}
+	defer func() {
+		registryMu.Lock()
+		delete(driverDialects, "anydriver")
+		registryMu.Unlock()
+	}()
+
+	actual, err := DetectDialect("anydriver")
+	if err != nil {
+		t.Fatalf("Expected registered driver to be detected, but got: %s", err)
+	}
+	if actual != MariaDB {
+		t.Fatalf("Expected %s but got %s", MariaDB.Name(), actual.Name())
+	}
+}
+
+type customTestDialect struct {
+	Dialect
+}
+
+func (customTestDialect) Name() string { return "custom" }
+
+func TestRegisterDialect(t *testing.T) {
+	custom := customTestDialect{Dialect: PostgreSQL}
+	err := RegisterDialect(custom, "customdriver")
+	if err != nil {
+		t.Fatalf("Expected RegisterDialect to succeed, but got: %s", err)
+	}
+	defer func() {
+		registryMu.Lock()
+		delete(dialects, "custom")
+		delete(driverDialects, "customdriver")
+		registryMu.Unlock()
+	}()
+
+	byName, ok := GetDialect("custom")
+	if !ok || byName != custom {
+		t.Fatalf("Expected custom dialect to be registered by name")
+	}
+
+	byDriver, _ := DetectDialect("customdriver")
+	if byDriver != custom {
+		t.Fatalf("Expected custom dialect to be registered for its driver")
+	}
+}
+
+func TestRegisterDialect_Nil(t *testing.T) {
+	err := RegisterDialect(nil)
+	expected := "dialect cannot be nil"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestRegisterDriver_Nil(t *testing.T) {
+	err := RegisterDriver("anydriver", nil)
+	expected := "dialect cannot be nil"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+	if _, err := DetectDialect("anydriver"); err == nil {
+		t.Fatalf("Expected the driver not to be registered")
+	}
+}
+
+func TestDialect_QuoteIdentifier(t *testing.T) {
+	tests := map[Dialect]string{
+		MySQL:      "`we``ird`",
+		MariaDB:    "`we``ird`",
+		PostgreSQL: "\"we`ird\"",
+		Oracle:     "\"we`ird\"",
+		SQLite:     "\"we`ird\"",
+		ODBC:       "\"we`ird\"",
+		SQLServer:  "[we`ird]",
+	}
+
+	for dialect, expected := range tests {
+		actual := dialect.QuoteIdentifier("we`ird")
+		if actual != expected {
+			t.Fatalf("Expected %s for %s but got %s", expected, dialect.Name(), actual)
+		}
+	}
+}
+
+func TestDialect_Placeholders_Offset(t *testing.T) {
+	tests := map[Dialect][]string{
+		MySQL:      {"?", "?"},
+		PostgreSQL: {"$3", "$4"},
+		Oracle:     {":val3", ":val4"},
+		SQLServer:  {"@p3", "@p4"},
+	}
+
+	for dialect, expected := range tests {
+		actual := dialect.Placeholders(2, 2, Values)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Expected %v for %s but got %v", expected, dialect.Name(), actual)
+		}
+	}
+}
+
+func TestSQLServer_QuoteIdentifier_EscapesBracket(t *testing.T) {
+	expected := "[we]]ird]"
+	actual := SQLServer.QuoteIdentifier("we]ird")
+
+	if actual != expected {
+		t.Fatalf("Expected %s but got %s", expected, actual)
+	}
+}
@@ -0,0 +1,306 @@
+package dvbcrud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter is a condition in a WHERE clause. Filters are created with Eq, Ne, Gt, Ge, Lt, Le,
+// In, Like, Between and IsNull, and combined with And, Or and Not.
+// Column names are checked against the db tags of the repository's model.
+type Filter interface {
+	render(w *whereBuilder) (string, error)
+}
+
+// whereBuilder renders filters into a WHERE clause and collects their arguments.
+type whereBuilder struct {
+	paramGen sqlParameterGenerator
+	quote    func(identifier string) string
+	columns  []string
+	offset   int
+	args     []any
+}
+
+// column validates name against the allowed columns and returns it quoted.
+func (w *whereBuilder) column(name string) (string, error) {
+	if !contains(w.columns, name) {
+		return "", fmt.Errorf("unknown column \"%s\" in filter", name)
+	}
+	return w.quote(name), nil
+}
+
+// placeholders adds values to the arguments and returns their placeholders.
+func (w *whereBuilder) placeholders(values ...any) ([]string, error) {
+	placeholders, err := w.paramGen.GetParamPlaceholders(w.offset+len(w.args), len(values), Columns)
+	if err != nil {
+		return nil, err
+	}
+	w.args = append(w.args, values...)
+	return placeholders, nil
+}
+
+type comparisonFilter struct {
+	column   string
+	operator string
+	value    any
+}
+
+func (f comparisonFilter) render(w *whereBuilder) (string, error) {
+	column, err := w.column(f.column)
+	if err != nil {
+		return "", err
+	}
+
+	placeholders, err := w.placeholders(f.value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %s %s", column, f.operator, placeholders[0]), nil
+}
+
+// Eq matches rows where column equals value.
+func Eq(column string, value any) Filter {
+	return comparisonFilter{column: column, operator: "=", value: value}
+}
+
+// Ne matches rows where column does not equal value.
+func Ne(column string, value any) Filter {
+	return comparisonFilter{column: column, operator: "<>", value: value}
+}
+
+// Gt matches rows where column is greater than value.
+func Gt(column string, value any) Filter {
+	return comparisonFilter{column: column, operator: ">", value: value}
+}
+
+// Ge matches rows where column is greater than or equal to value.
+func Ge(column string, value any) Filter {
+	return comparisonFilter{column: column, operator: ">=", value: value}
+}
+
+// Lt matches rows where column is less than value.
+func Lt(column string, value any) Filter {
+	return comparisonFilter{column: column, operator: "<", value: value}
+}
+
+// Le matches rows where column is less than or equal to value.
+func Le(column string, value any) Filter {
+	return comparisonFilter{column: column, operator: "<=", value: value}
+}
+
+// Like matches rows where column matches pattern.
+func Like(column string, pattern string) Filter {
+	return comparisonFilter{column: column, operator: "LIKE", value: pattern}
+}
+
+type inFilter struct {
+	column string
+	values []any
+}
+
+func (f inFilter) render(w *whereBuilder) (string, error) {
+	column, err := w.column(f.column)
+	if err != nil {
+		return "", err
+	}
+
+	// IN () is invalid SQL, and no value can match an empty list
+	if len(f.values) == 0 {
+		return "1 = 0", nil
+	}
+
+	placeholders, err := w.placeholders(f.values...)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")), nil
+}
+
+// In matches rows where column equals any of values.
+func In(column string, values ...any) Filter {
+	return inFilter{column: column, values: values}
+}
+
+type betweenFilter struct {
+	column string
+	low    any
+	high   any
+}
+
+func (f betweenFilter) render(w *whereBuilder) (string, error) {
+	column, err := w.column(f.column)
+	if err != nil {
+		return "", err
+	}
+
+	placeholders, err := w.placeholders(f.low, f.high)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s BETWEEN %s AND %s", column, placeholders[0], placeholders[1]), nil
+}
+
+// Between matches rows where column is between low and high, inclusive.
+func Between(column string, low any, high any) Filter {
+	return betweenFilter{column: column, low: low, high: high}
+}
+
+type nullFilter struct {
+	column string
+	isNull bool
+}
+
+func (f nullFilter) render(w *whereBuilder) (string, error) {
+	column, err := w.column(f.column)
+	if err != nil {
+		return "", err
+	}
+
+	if f.isNull {
+		return column + " IS NULL", nil
+	}
+	return column + " IS NOT NULL", nil
+}
+
+// IsNull matches rows where column is NULL.
+func IsNull(column string) Filter {
+	return nullFilter{column: column, isNull: true}
+}
+
+// IsNotNull matches rows where column is not NULL.
+func IsNotNull(column string) Filter {
+	return nullFilter{column: column, isNull: false}
+}
+
+type logicalFilter struct {
+	operator string
+	filters  []Filter
+}
+
+func (f logicalFilter) render(w *whereBuilder) (string, error) {
+	var conditions []string
+	for _, filter := range f.filters {
+		if filter == nil {
+			continue
+		}
+
+		condition, err := filter.render(w)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, "("+condition+")")
+	}
+
+	if len(conditions) == 0 {
+		// An empty AND matches everything, an empty OR matches nothing
+		if f.operator == "AND" {
+			return "1 = 1", nil
+		}
+		return "1 = 0", nil
+	}
+	if len(conditions) == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(conditions[0], "("), ")"), nil
+	}
+
+	return strings.Join(conditions, " "+f.operator+" "), nil
+}
+
+// And matches rows that match all of filters. nil filters are ignored.
+func And(filters ...Filter) Filter {
+	return logicalFilter{operator: "AND", filters: filters}
+}
+
+// Or matches rows that match any of filters. nil filters are ignored.
+func Or(filters ...Filter) Filter {
+	return logicalFilter{operator: "OR", filters: filters}
+}
+
+type notFilter struct {
+	filter Filter
+}
+
+func (f notFilter) render(w *whereBuilder) (string, error) {
+	if f.filter == nil {
+		return "", fmt.Errorf("cannot negate a nil filter")
+	}
+
+	condition, err := f.filter.render(w)
+	if err != nil {
+		return "", err
+	}
+
+	return "NOT (" + condition + ")", nil
+}
+
+// Not matches rows that do not match filter.
+func Not(filter Filter) Filter {
+	return notFilter{filter: filter}
+}
+
+type rowValueFilter struct {
+	columns  []string
+	operator string
+	values   []any
+}
+
+func (f rowValueFilter) render(w *whereBuilder) (string, error) {
+	columns := make([]string, len(f.columns))
+	for i, name := range f.columns {
+		column, err := w.column(name)
+		if err != nil {
+			return "", err
+		}
+		columns[i] = column
+	}
+
+	placeholders, err := w.placeholders(f.values...)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), f.operator, strings.Join(placeholders, ", ")), nil
+}
+
+// seekFilter matches the rows that come after values when sorted by orderBy, where values
+// holds the sort keys of a row in the same order. When all columns are sorted in the same
+// direction and rowValues is set, the rows are sought with a single row value comparison.
+// Otherwise, the comparison is expanded into (a > ?) OR (a = ? AND b > ?) and so on.
+func seekFilter(orderBy []OrderBy, values []any, rowValues bool) Filter {
+	sameDirection := true
+	for _, order := range orderBy {
+		sameDirection = sameDirection && order.Descending == orderBy[0].Descending
+	}
+
+	if rowValues && sameDirection && len(orderBy) > 1 {
+		columns := make([]string, len(orderBy))
+		for i, order := range orderBy {
+			columns[i] = order.Column
+		}
+
+		operator := ">"
+		if orderBy[0].Descending {
+			operator = "<"
+		}
+		return rowValueFilter{columns: columns, operator: operator, values: values}
+	}
+
+	alternatives := make([]Filter, len(orderBy))
+	for i, order := range orderBy {
+		conditions := make([]Filter, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, Eq(orderBy[j].Column, values[j]))
+		}
+
+		if order.Descending {
+			conditions = append(conditions, Lt(order.Column, values[i]))
+		} else {
+			conditions = append(conditions, Gt(order.Column, values[i]))
+		}
+		alternatives[i] = And(conditions...)
+	}
+
+	return Or(alternatives...)
+}
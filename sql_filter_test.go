@@ -0,0 +1,141 @@
+package dvbcrud
+
+import (
+	"reflect"
+	"testing"
+)
+
+var filterTestColumns = []string{"id", "name", "age", "deleted_at", "order"}
+
+func renderFilter(dialect Dialect, filter Filter, offset int) (string, []any, error) {
+	sqlGen := newSQLGenerator(newSQLParamGen(dialect), newSQLIdentifierQuoter(dialect, QuoteReserved), dialect)
+	return sqlGen.GenerateWhere(filter, filterTestColumns, offset)
+}
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		filter   Filter
+		expected string
+		args     []any
+	}{
+		{Eq("name", "Ann"), "name = ?", []any{"Ann"}},
+		{Ne("name", "Ann"), "name <> ?", []any{"Ann"}},
+		{Gt("age", 18), "age > ?", []any{18}},
+		{Ge("age", 18), "age >= ?", []any{18}},
+		{Lt("age", 18), "age < ?", []any{18}},
+		{Le("age", 18), "age <= ?", []any{18}},
+		{Like("name", "A%"), "name LIKE ?", []any{"A%"}},
+		{In("id", 1, 2, 3), "id IN (?, ?, ?)", []any{1, 2, 3}},
+		{In("id"), "1 = 0", nil},
+		{Between("age", 18, 65), "age BETWEEN ? AND ?", []any{18, 65}},
+		{IsNull("deleted_at"), "deleted_at IS NULL", nil},
+		{IsNotNull("deleted_at"), "deleted_at IS NOT NULL", nil},
+		{Eq("order", 1), "`order` = ?", []any{1}},
+		{And(Eq("name", "Ann"), Gt("age", 18)), "(name = ?) AND (age > ?)", []any{"Ann", 18}},
+		{Or(Eq("name", "Ann"), IsNull("name")), "(name = ?) OR (name IS NULL)", []any{"Ann"}},
+		{And(Eq("name", "Ann"), nil), "name = ?", []any{"Ann"}},
+		{And(), "1 = 1", nil},
+		{Or(), "1 = 0", nil},
+		{Not(In("id", 1, 2)), "NOT (id IN (?, ?))", []any{1, 2}},
+		{Not(And(Eq("id", 1), Eq("age", 2))), "NOT ((id = ?) AND (age = ?))", []any{1, 2}},
+	}
+
+	for _, test := range tests {
+		actual, args, err := renderFilter(MySQL, test.filter, 0)
+		if err != nil {
+			t.Fatalf("Expected \"%s\" but got %v", test.expected, err)
+		}
+		if actual != test.expected {
+			t.Fatalf("Expected \"%s\" but got \"%s\"", test.expected, actual)
+		}
+		if !reflect.DeepEqual(args, test.args) {
+			t.Fatalf("Expected args %v but got %v", test.args, args)
+		}
+	}
+}
+
+func TestFilters_PlaceholderNumbering(t *testing.T) {
+	filter := And(In("id", 1, 2), Or(Eq("name", "Ann"), Between("age", 18, 65)))
+	tests := map[Dialect]string{
+		PostgreSQL: "(id IN ($3, $4)) AND ((name = $5) OR (age BETWEEN $6 AND $7))",
+		SQLServer:  "(id IN (@p3, @p4)) AND ((name = @p5) OR (age BETWEEN @p6 AND @p7))",
+		Oracle:     "(id IN (:col3, :col4)) AND ((name = :col5) OR (age BETWEEN :col6 AND :col7))",
+	}
+
+	for dialect, expected := range tests {
+		actual, _, err := renderFilter(dialect, filter, 2)
+		if err != nil || actual != expected {
+			t.Fatalf("Expected \"%s\" for %s but got \"%s\" (%v)", expected, dialect.Name(), actual, err)
+		}
+	}
+}
+
+func TestFilters_UnknownColumn(t *testing.T) {
+	expected := "unknown column \"name; DROP TABLE x\" in filter"
+	filters := []Filter{
+		Eq("name; DROP TABLE x", 1),
+		In("name; DROP TABLE x", 1),
+		Between("name; DROP TABLE x", 1, 2),
+		IsNull("name; DROP TABLE x"),
+		Or(Eq("id", 1), Not(Eq("name; DROP TABLE x", 1))),
+	}
+
+	for _, filter := range filters {
+		_, _, actual := renderFilter(MySQL, filter, 0)
+		if actual == nil || actual.Error() != expected {
+			t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+		}
+	}
+}
+
+func TestFilters_NotNil(t *testing.T) {
+	expected := "cannot negate a nil filter"
+
+	_, _, actual := renderFilter(MySQL, Not(nil), 0)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestFilters_Nil(t *testing.T) {
+	actual, args, err := renderFilter(MySQL, nil, 0)
+
+	if actual != "" || args != nil || err != nil {
+		t.Fatalf("Expected empty condition but got \"%s\", %v, %v", actual, args, err)
+	}
+}
+
+func TestSeekFilter(t *testing.T) {
+	tests := []struct {
+		orderBy   []OrderBy
+		rowValues bool
+		expected  string
+	}{
+		{[]OrderBy{Asc("id")}, true, "id > ?"},
+		{[]OrderBy{Desc("id")}, false, "id < ?"},
+		{[]OrderBy{Asc("age"), Asc("id")}, true, "(age, id) > (?, ?)"},
+		{[]OrderBy{Desc("age"), Desc("id")}, true, "(age, id) < (?, ?)"},
+		{[]OrderBy{Asc("age"), Asc("id")}, false, "(age > ?) OR ((age = ?) AND (id > ?))"},
+		{[]OrderBy{Desc("age"), Asc("id")}, true, "(age < ?) OR ((age = ?) AND (id > ?))"},
+	}
+
+	for _, test := range tests {
+		values := make([]any, len(test.orderBy))
+		actual, _, err := renderFilter(MySQL, seekFilter(test.orderBy, values, test.rowValues), 0)
+		if err != nil || actual != test.expected {
+			t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", test.expected, actual, err)
+		}
+	}
+}
+
+func TestSeekFilter_Args(t *testing.T) {
+	orderBy := []OrderBy{Asc("age"), Asc("name"), Asc("id")}
+
+	_, actual, _ := renderFilter(SQLServer, seekFilter(orderBy, []any{30, "Ann", 7}, false), 0)
+
+	expected := []any{30, 30, "Ann", 30, "Ann", 7}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
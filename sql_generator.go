@@ -7,92 +7,629 @@ import (
 
 type sqlGenerator interface {
 	// GenerateSelect generates and returns a SELECT statement (WHERE ID)
-	GenerateSelect(table string, idField string, fields []string) (string, error)
+	GenerateSelect(table string, idFields []string, fields []string) (string, error)
 
 	// GenerateSelectAll generates and returns a SELECT statement (all rows)
 	GenerateSelectAll(table string, fields []string) string
 
-	// GenerateInsert TODO: docs
+	// GenerateWhere renders filter into a condition for a WHERE clause and returns it with its
+	// arguments. Column names must be in columns, and placeholders are numbered from offset.
+	// A nil filter returns an empty condition.
+	GenerateWhere(filter Filter, columns []string, offset int) (string, []any, error)
+
+	// GenerateSelectWhere returns SELECT <fields> FROM <table> WHERE <where>.
+	// The WHERE clause is left out when where is empty.
+	GenerateSelectWhere(table string, fields []string, where string) string
+
+	// GenerateSelectForUpdate returns SELECT <fields> FROM <table> WHERE <where> with the row locks
+	// described by lock, using the locking syntax of the dialect. The rows are sorted by orderBy
+	// unless it is empty, and limited to limit rows unless it is 0.
+	// The WHERE clause is left out when where is empty.
+	GenerateSelectForUpdate(table string, fields []string, where string, orderBy string, limit int, lock rowLock) (string, error)
+
+	// GenerateOrderBy renders orderBy into the columns of an ORDER BY clause.
+	// Column names must be in columns.
+	GenerateOrderBy(orderBy []OrderBy, columns []string) (string, error)
+
+	// GenerateSelectPage returns a SELECT statement that skips offset rows and returns at most
+	// limit rows, sorted by orderBy, using the pagination syntax of the dialect.
+	// The WHERE clause is left out when where is empty.
+	GenerateSelectPage(table string, fields []string, where string, orderBy string, limit int, offset int) (string, error)
+
+	// GenerateAggregate returns SELECT <fn>(<column>) FROM <table> WHERE <where>, which also selects
+	// and groups by groupBy unless it is empty. An empty column counts all rows with COUNT(*).
+	// The WHERE clause is left out when where is empty.
+	GenerateAggregate(table string, fn AggregateFunc, column string, groupBy string, where string) (string, error)
+
+	// GenerateCount returns SELECT COUNT(*) FROM <table> WHERE <where>.
+	// The WHERE clause is left out when where is empty.
+	GenerateCount(table string, where string) string
+
+	// GenerateInsert returns INSERT INTO <table> (<fields>) VALUES (?, ...)
 	GenerateInsert(table string, fields []string) (string, error)
 
-	// GenerateUpdate TODO: docs
-	GenerateUpdate(table string, idField string, fields []string) (string, error)
+	// GenerateInsertMany returns an INSERT INTO statement for rows rows, using the multi-row
+	// syntax of the dialect. Placeholders are numbered across all rows.
+	GenerateInsertMany(table string, fields []string, rows int) (string, error)
+
+	// GenerateInsertReturning returns an INSERT INTO statement that also returns generated values,
+	// using the RETURNING, OUTPUT INSERTED or RETURNING INTO syntax of the dialect.
+	// Dialects that use LastInsertId get a plain INSERT INTO statement.
+	GenerateInsertReturning(table string, idField string, fields []string, returnFields []string) (string, error)
+
+	// GenerateUpsert returns a statement that inserts fields, or updates updateFields if a row
+	// with the same conflictFields exists, using the upsert syntax of the dialect.
+	// The update also increments the increments columns by one.
+	GenerateUpsert(table string, fields []string, conflictFields []string, updateFields []string, increments []string) (string, error)
+
+	// GenerateUpdate returns UPDATE <table> SET <field> = ?, ..., <increment> = <increment> + 1, ... WHERE <id> = ? AND ...
+	// The ID placeholders are numbered after the field placeholders.
+	GenerateUpdate(table string, idFields []string, fields []string, increments []string) (string, error)
+
+	// GenerateUpdateWhere returns UPDATE <table> SET <field> = ?, ..., <increment> = <increment> + 1, ... WHERE <where>.
+	// The placeholders in where must be numbered after the field placeholders.
+	GenerateUpdateWhere(table string, fields []string, increments []string, where string) (string, error)
 
-	// GenerateDelete returns DELETE FROM <table> WHERE <id> = ?
-	GenerateDelete(table string, idField string) (string, error)
+	// GenerateClaim returns an UPDATE ... RETURNING statement that claims at most limit of the rows
+	// matching where, sorted by orderBy, for a Queue. It sets the status and visible at columns to
+	// the first two placeholders and increments the attempts and increments columns. The placeholders
+	// in where must be numbered after these two.
+	GenerateClaim(table string, idFields []string, fields []string, increments []string, columns queueColumns, where string, orderBy string, limit int) (string, error)
+
+	// GenerateDelete returns DELETE FROM <table> WHERE <id> = ? AND ...
+	GenerateDelete(table string, idFields []string) (string, error)
+
+	// GenerateDeleteWhere returns DELETE FROM <table> WHERE <where>
+	GenerateDeleteWhere(table string, where string) (string, error)
 }
 
 type sqlGeneratorImpl struct {
 	sqlGenerator
 	paramGen sqlParameterGenerator
+	quoter   sqlIdentifierQuoter
+	dialect  Dialect
+}
+
+// quote quotes identifier, or returns it as-is when no quoter is set.
+func (s sqlGeneratorImpl) quote(identifier string) string {
+	if s.quoter == nil {
+		return identifier
+	}
+	return s.quoter.Quote(identifier)
 }
 
-func (s sqlGeneratorImpl) GenerateSelect(table string, idField string, fields []string) (string, error) {
-	placeholders, err := s.paramGen.GetParamPlaceholders(1, Columns)
+// quoteAll quotes each of identifiers and returns them as a new slice.
+func (s sqlGeneratorImpl) quoteAll(identifiers []string) []string {
+	quoted := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		quoted[i] = s.quote(identifier)
+	}
+	return quoted
+}
+
+// setIncrements returns <increment> = <qualifier><increment> + 1 for each of increments.
+func (s sqlGeneratorImpl) setIncrements(increments []string, qualifier string) []string {
+	set := make([]string, len(increments))
+	for i, increment := range increments {
+		quoted := s.quote(increment)
+		set[i] = quoted + " = " + qualifier + quoted + " + 1"
+	}
+	return set
+}
+
+// keyCondition returns <id> = ? AND ... for idFields, with placeholders numbered from offset.
+func (s sqlGeneratorImpl) keyCondition(idFields []string, offset int) (string, error) {
+	if len(idFields) == 0 {
+		return "", fmt.Errorf("at least one ID field is required")
+	}
+
+	placeholders, err := s.paramGen.GetParamPlaceholders(offset, len(idFields), Columns)
+	if err != nil {
+		return "", err
+	}
+
+	conditions := make([]string, len(idFields))
+	for i, idField := range idFields {
+		conditions[i] = s.quote(idField) + " = " + placeholders[i]
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+func (s sqlGeneratorImpl) GenerateSelect(table string, idFields []string, fields []string) (string, error) {
+	condition, err := s.keyCondition(idFields, 0)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("SELECT %s FROM %s WHERE %s = %s",
-		strings.Join(fields, ", "),
-		table,
-		idField,
-		placeholders[0]), nil
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		strings.Join(s.quoteAll(fields), ", "),
+		s.quote(table),
+		condition), nil
 }
 
 func (s sqlGeneratorImpl) GenerateSelectAll(table string, fields []string) string {
-	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(fields, ", "), table)
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(s.quoteAll(fields), ", "), s.quote(table))
+}
+
+func (s sqlGeneratorImpl) GenerateWhere(filter Filter, columns []string, offset int) (string, []any, error) {
+	if filter == nil {
+		return "", nil, nil
+	}
+
+	w := whereBuilder{
+		paramGen: s.paramGen,
+		quote:    s.quote,
+		columns:  columns,
+		offset:   offset,
+	}
+
+	where, err := filter.render(&w)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return where, w.args, nil
+}
+
+func (s sqlGeneratorImpl) GenerateSelectWhere(table string, fields []string, where string) string {
+	sql := s.GenerateSelectAll(table, fields)
+	if where == "" {
+		return sql
+	}
+	return sql + " WHERE " + where
+}
+
+func (s sqlGeneratorImpl) GenerateSelectForUpdate(table string, fields []string, where string, orderBy string, limit int, lock rowLock) (string, error) {
+	style := s.dialect.Locking()
+	if style == LockingUnsupported {
+		return "", fmt.Errorf("dialect %s cannot lock rows", s.dialect.Name())
+	}
+	if style == LockingForUpdateOnly && lock.shared {
+		return "", fmt.Errorf("dialect %s cannot take shared row locks", s.dialect.Name())
+	}
+	if limit < 0 {
+		return "", fmt.Errorf("limit cannot be negative")
+	}
+
+	if style == LockingTableHints {
+		hints := "UPDLOCK, ROWLOCK"
+		if lock.shared {
+			// Not HOLDLOCK, which is SERIALIZABLE and cannot be combined with READPAST
+			hints = "REPEATABLEREAD, ROWLOCK"
+		}
+		if lock.noWait {
+			hints += ", NOWAIT"
+		}
+		if lock.skipLocked {
+			hints += ", READPAST"
+		}
+
+		top := ""
+		if limit > 0 {
+			top = fmt.Sprintf("TOP (%d) ", limit)
+		}
+
+		sql := fmt.Sprintf("SELECT %s%s FROM %s WITH (%s)", top, strings.Join(s.quoteAll(fields), ", "), s.quote(table), hints)
+		if where != "" {
+			sql += " WHERE " + where
+		}
+		if orderBy != "" {
+			sql += " ORDER BY " + orderBy
+		}
+		return sql, nil
+	}
+
+	sql := s.GenerateSelectWhere(table, fields, where)
+	if orderBy != "" {
+		sql += " ORDER BY " + orderBy
+	}
+	if limit > 0 {
+		// Oracle rejects FETCH NEXT in locking queries, and ROWNUM is applied before locked rows are skipped
+		if s.dialect.Pagination() != PaginationLimitOffset {
+			return "", fmt.Errorf("dialect %s cannot limit locked rows", s.dialect.Name())
+		}
+		sql += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	switch {
+	case !lock.shared:
+		sql += " FOR UPDATE"
+	case style == LockingLockInShareMode:
+		sql += " LOCK IN SHARE MODE"
+	default:
+		sql += " FOR SHARE"
+	}
+
+	if lock.noWait {
+		sql += " NOWAIT"
+	}
+	if lock.skipLocked {
+		sql += " SKIP LOCKED"
+	}
+	return sql, nil
+}
+
+func (s sqlGeneratorImpl) GenerateOrderBy(orderBy []OrderBy, columns []string) (string, error) {
+	if len(orderBy) == 0 {
+		return "", fmt.Errorf("order by requires at least one column")
+	}
+
+	terms := make([]string, len(orderBy))
+	for i, order := range orderBy {
+		if !contains(columns, order.Column) {
+			return "", fmt.Errorf("unknown column \"%s\" in order by", order.Column)
+		}
+
+		terms[i] = s.quote(order.Column)
+		if order.Descending {
+			terms[i] += " DESC"
+		}
+	}
+
+	return strings.Join(terms, ", "), nil
+}
+
+func (s sqlGeneratorImpl) GenerateSelectPage(table string, fields []string, where string, orderBy string, limit int, offset int) (string, error) {
+	if limit < 1 {
+		return "", fmt.Errorf("limit must be at least 1")
+	}
+	if offset < 0 {
+		return "", fmt.Errorf("offset cannot be negative")
+	}
+
+	sql := s.GenerateSelectWhere(table, fields, where) + " ORDER BY " + orderBy
+
+	switch s.dialect.Pagination() {
+	case PaginationLimitOffset:
+		return fmt.Sprintf("%s LIMIT %d OFFSET %d", sql, limit, offset), nil
+
+	case PaginationOffsetFetch:
+		return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", sql, offset, limit), nil
+
+	case PaginationRowNum:
+		// The row number is only selected in the middle query, so it isn't scanned into the model
+		return fmt.Sprintf("SELECT %s FROM (SELECT page.*, ROWNUM page_rownum FROM (%s) page WHERE ROWNUM <= %d) WHERE page_rownum > %d",
+			strings.Join(s.quoteAll(fields), ", "), sql, offset+limit, offset), nil
+
+	default:
+		return "", fmt.Errorf("dialect %s does not support pagination", s.dialect.Name())
+	}
+}
+
+func (s sqlGeneratorImpl) GenerateAggregate(table string, fn AggregateFunc, column string, groupBy string, where string) (string, error) {
+	var name string
+	switch fn {
+	case AggregateCount:
+		name = "COUNT"
+	case AggregateSum:
+		name = "SUM"
+	case AggregateMin:
+		name = "MIN"
+	case AggregateMax:
+		name = "MAX"
+	case AggregateAvg:
+		name = "AVG"
+	default:
+		return "", fmt.Errorf("unknown aggregate function %d", fn)
+	}
+
+	argument := "*"
+	if column != "" {
+		argument = s.quote(column)
+	} else if fn != AggregateCount {
+		return "", fmt.Errorf("%s requires a column", name)
+	}
+
+	selection := fmt.Sprintf("%s(%s)", name, argument)
+	if groupBy != "" {
+		selection = s.quote(groupBy) + ", " + selection
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s", selection, s.quote(table))
+	if where != "" {
+		sql += " WHERE " + where
+	}
+	if groupBy != "" {
+		sql += " GROUP BY " + s.quote(groupBy)
+	}
+	return sql, nil
+}
+
+func (s sqlGeneratorImpl) GenerateCount(table string, where string) string {
+	sql := "SELECT COUNT(*) FROM " + s.quote(table)
+	if where == "" {
+		return sql
+	}
+	return sql + " WHERE " + where
 }
 
 func (s sqlGeneratorImpl) GenerateInsert(table string, fields []string) (string, error) {
-	placeholders, err := s.paramGen.GetParamPlaceholders(len(fields), Values)
+	placeholders, err := s.paramGen.GetParamPlaceholders(0, len(fields), Values)
 	if err != nil {
 		return "", err
 	}
 
 	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		table,
-		strings.Join(fields, ", "),
+		s.quote(table),
+		strings.Join(s.quoteAll(fields), ", "),
 		strings.Join(placeholders, ", ")), nil
 }
 
-func (s sqlGeneratorImpl) GenerateUpdate(table string, idField string, fields []string) (string, error) {
-	columnPlaceholders, err := s.paramGen.GetParamPlaceholders(1, Columns)
+func (s sqlGeneratorImpl) GenerateInsertMany(table string, fields []string, rows int) (string, error) {
+	if rows < 1 {
+		return "", fmt.Errorf("rows must be at least 1")
+	}
+
+	values := make([]string, rows)
+	for i := 0; i < rows; i++ {
+		placeholders, err := s.paramGen.GetParamPlaceholders(i*len(fields), len(fields), Values)
+		if err != nil {
+			return "", err
+		}
+		values[i] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	into := fmt.Sprintf("%s (%s)", s.quote(table), strings.Join(s.quoteAll(fields), ", "))
+
+	style := s.dialect.MultiRowInsert()
+	if rows == 1 && style == MultiRowInsertUnsupported {
+		style = MultiRowInsertValues
+	}
+
+	switch style {
+	case MultiRowInsertValues:
+		return fmt.Sprintf("INSERT INTO %s VALUES %s", into, strings.Join(values, ", ")), nil
+
+	case MultiRowInsertAll:
+		var sb strings.Builder
+		sb.WriteString("INSERT ALL")
+		for _, value := range values {
+			sb.WriteString(" INTO " + into + " VALUES " + value)
+		}
+		sb.WriteString(" SELECT 1 FROM DUAL")
+		return sb.String(), nil
+
+	default:
+		return "", fmt.Errorf("dialect %s cannot insert several rows in one statement", s.dialect.Name())
+	}
+}
+
+func (s sqlGeneratorImpl) GenerateInsertReturning(table string, idField string, fields []string, returnFields []string) (string, error) {
+	placeholders, err := s.paramGen.GetParamPlaceholders(0, len(fields), Values)
+	if err != nil {
+		return "", err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s)", s.quote(table), strings.Join(s.quoteAll(fields), ", "))
+	values := fmt.Sprintf("VALUES (%s)", strings.Join(placeholders, ", "))
+
+	switch s.dialect.Returning() {
+	case ReturningLastInsertID:
+		return insert + " " + values, nil
+
+	case ReturningClause:
+		return fmt.Sprintf("%s %s RETURNING %s", insert, values, strings.Join(s.quoteAll(returnFields), ", ")), nil
+
+	case ReturningOutput:
+		output := make([]string, len(returnFields))
+		for i, field := range returnFields {
+			output[i] = "INSERTED." + s.quote(field)
+		}
+		return fmt.Sprintf("%s OUTPUT %s %s", insert, strings.Join(output, ", "), values), nil
+
+	case ReturningInto:
+		into, err := s.paramGen.GetParamPlaceholders(len(fields), 1, Columns)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s RETURNING %s INTO %s", insert, values, s.quote(idField), into[0]), nil
+
+	default:
+		return "", fmt.Errorf("dialect %s cannot return generated values", s.dialect.Name())
+	}
+}
+
+func (s sqlGeneratorImpl) GenerateUpsert(table string, fields []string, conflictFields []string, updateFields []string, increments []string) (string, error) {
+	if len(conflictFields) == 0 {
+		return "", fmt.Errorf("upsert requires at least one conflict field")
+	}
+
+	placeholders, err := s.paramGen.GetParamPlaceholders(0, len(fields), Values)
+	if err != nil {
+		return "", err
+	}
+
+	quotedTable := s.quote(table)
+	quotedFields := s.quoteAll(fields)
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quotedTable,
+		strings.Join(quotedFields, ", "),
+		strings.Join(placeholders, ", "))
+
+	switch s.dialect.Upsert() {
+	case UpsertOnConflict:
+		conflict := strings.Join(s.quoteAll(conflictFields), ", ")
+		if len(updateFields) == 0 {
+			return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", insert, conflict), nil
+		}
+
+		set := make([]string, len(updateFields))
+		for i, field := range updateFields {
+			set[i] = s.quote(field) + " = EXCLUDED." + s.quote(field)
+		}
+		// Unqualified columns would be ambiguous with EXCLUDED
+		set = append(set, s.setIncrements(increments, quotedTable+".")...)
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", insert, conflict, strings.Join(set, ", ")), nil
+
+	case UpsertOnDuplicateKey:
+		var set []string
+		for _, field := range updateFields {
+			set = append(set, s.quote(field)+" = VALUES("+s.quote(field)+")")
+		}
+		if len(set) == 0 {
+			// Assigning a conflict field to itself turns the duplicate into a no-op
+			set = append(set, s.quote(conflictFields[0])+" = "+s.quote(conflictFields[0]))
+		} else {
+			set = append(set, s.setIncrements(increments, "")...)
+		}
+		return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", insert, strings.Join(set, ", ")), nil
+
+	case UpsertMerge, UpsertMergeFromDual:
+		source := make([]string, len(fields))
+		insertValues := make([]string, len(fields))
+		for i := range fields {
+			source[i] = placeholders[i] + " AS " + quotedFields[i]
+			insertValues[i] = "source." + quotedFields[i]
+		}
+
+		on := make([]string, len(conflictFields))
+		for i, field := range conflictFields {
+			on[i] = "target." + s.quote(field) + " = source." + s.quote(field)
+		}
+
+		var sb strings.Builder
+		if s.dialect.Upsert() == UpsertMergeFromDual {
+			sb.WriteString(fmt.Sprintf("MERGE INTO %s target USING (SELECT %s FROM DUAL) source ON (%s)",
+				quotedTable, strings.Join(source, ", "), strings.Join(on, " AND ")))
+		} else {
+			// HOLDLOCK keeps concurrent upserts of the same key from both taking the NOT MATCHED branch
+			sb.WriteString(fmt.Sprintf("MERGE INTO %s WITH (HOLDLOCK) AS target USING (SELECT %s) AS source ON (%s)",
+				quotedTable, strings.Join(source, ", "), strings.Join(on, " AND ")))
+		}
+
+		if len(updateFields) > 0 {
+			set := make([]string, len(updateFields))
+			for i, field := range updateFields {
+				set[i] = "target." + s.quote(field) + " = source." + s.quote(field)
+			}
+			for _, increment := range s.setIncrements(increments, "target.") {
+				set = append(set, "target."+increment)
+			}
+			sb.WriteString(" WHEN MATCHED THEN UPDATE SET " + strings.Join(set, ", "))
+		}
+
+		sb.WriteString(fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+			strings.Join(quotedFields, ", "), strings.Join(insertValues, ", ")))
+
+		// SQL Server requires MERGE to be terminated, Oracle rejects the terminator
+		if s.dialect.Upsert() == UpsertMerge {
+			sb.WriteString(";")
+		}
+		return sb.String(), nil
+
+	default:
+		return "", fmt.Errorf("dialect %s does not support upserts", s.dialect.Name())
+	}
+}
+
+func (s sqlGeneratorImpl) GenerateUpdate(table string, idFields []string, fields []string, increments []string) (string, error) {
+	condition, err := s.keyCondition(idFields, len(fields))
 	if err != nil {
 		return "", err
 	}
 
-	valuePlaceholders, err := s.paramGen.GetParamPlaceholders(len(fields), Values)
+	valuePlaceholders, err := s.paramGen.GetParamPlaceholders(0, len(fields), Values)
 	if err != nil {
 		return "", err
 	}
 
-	f := fields
-	for i := range f {
-		f[i] += " = " + valuePlaceholders[i]
+	f := make([]string, len(fields))
+	for i := range fields {
+		f[i] = s.quote(fields[i]) + " = " + valuePlaceholders[i]
 	}
+	f = append(f, s.setIncrements(increments, "")...)
 
-	return fmt.Sprintf("UPDATE %s SET (%s) WHERE %s = %s",
-		table,
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		s.quote(table),
 		strings.Join(f, ", "),
-		idField,
-		columnPlaceholders[0]), nil
+		condition), nil
 }
 
-func (s sqlGeneratorImpl) GenerateDelete(table string, idField string) (string, error) {
-	placeholder, err := s.paramGen.GetParamPlaceholders(1, Columns)
+func (s sqlGeneratorImpl) GenerateUpdateWhere(table string, fields []string, increments []string, where string) (string, error) {
+	if where == "" {
+		return "", fmt.Errorf("UPDATE statement requires a condition")
+	}
+
+	valuePlaceholders, err := s.paramGen.GetParamPlaceholders(0, len(fields), Values)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("DELETE FROM %s WHERE %s = %s",
-		table,
-		idField,
-		placeholder[0]), nil
+	f := make([]string, len(fields))
+	for i := range fields {
+		f[i] = s.quote(fields[i]) + " = " + valuePlaceholders[i]
+	}
+	f = append(f, s.setIncrements(increments, "")...)
+
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		s.quote(table),
+		strings.Join(f, ", "),
+		where), nil
+}
+
+func (s sqlGeneratorImpl) GenerateClaim(table string, idFields []string, fields []string, increments []string, columns queueColumns, where string, orderBy string, limit int) (string, error) {
+	if s.dialect.Returning() != ReturningClause || s.dialect.Pagination() != PaginationLimitOffset {
+		return "", fmt.Errorf("dialect %s cannot claim rows with UPDATE ... RETURNING", s.dialect.Name())
+	}
+	if len(idFields) == 0 {
+		return "", fmt.Errorf("at least one ID field is required")
+	}
+	if limit < 1 {
+		return "", fmt.Errorf("limit must be at least 1")
+	}
+
+	placeholders, err := s.paramGen.GetParamPlaceholders(0, 2, Values)
+	if err != nil {
+		return "", err
+	}
+
+	key := strings.Join(s.quoteAll(idFields), ", ")
+	if len(idFields) > 1 {
+		key = "(" + key + ")"
+	}
+
+	subquery := s.GenerateSelectWhere(table, idFields, where)
+	if orderBy != "" {
+		subquery += " ORDER BY " + orderBy
+	}
+	subquery += fmt.Sprintf(" LIMIT %d", limit)
+
+	set := []string{
+		s.quote(columns.status) + " = " + placeholders[0],
+		s.quote(columns.visibleAt) + " = " + placeholders[1],
+	}
+	set = append(set, s.setIncrements(append([]string{columns.attempts}, increments...), "")...)
+
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s IN (%s) RETURNING %s",
+		s.quote(table),
+		strings.Join(set, ", "),
+		key, subquery,
+		strings.Join(s.quoteAll(fields), ", ")), nil
+}
+
+func (s sqlGeneratorImpl) GenerateDelete(table string, idFields []string) (string, error) {
+	condition, err := s.keyCondition(idFields, 0)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("DELETE FROM %s WHERE %s",
+		s.quote(table),
+		condition), nil
+}
+
+func (s sqlGeneratorImpl) GenerateDeleteWhere(table string, where string) (string, error) {
+	if where == "" {
+		return "", fmt.Errorf("DELETE statement requires a condition")
+	}
+
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", s.quote(table), where), nil
 }
 
-func newSQLGenerator(paramGen sqlParameterGenerator) sqlGenerator {
+func newSQLGenerator(paramGen sqlParameterGenerator, quoter sqlIdentifierQuoter, dialect Dialect) sqlGenerator {
 	return &sqlGeneratorImpl{
 		paramGen: paramGen,
+		quoter:   quoter,
+		dialect:  dialect,
 	}
 }
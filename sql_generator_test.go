@@ -9,13 +9,13 @@ import (
 func newSqlParameterGeneratorMock(err error) sqlParameterGeneratorMock {
     if err != nil {
         return sqlParameterGeneratorMock{
-            GetParamPlaceholdersMock: func(amount int, typ parameterType) ([]string, error) {
+            GetParamPlaceholdersMock: func(offset int, amount int, typ ParameterType) ([]string, error) {
                 return nil, err
             },
         }
     }
     return sqlParameterGeneratorMock{
-        GetParamPlaceholdersMock: func(amount int, typ parameterType) ([]string, error) {
+        GetParamPlaceholdersMock: func(offset int, amount int, typ ParameterType) ([]string, error) {
             placeholders := make([]string, amount)
             for i := 0; i < amount; i++ {
                 placeholders[i] = "?"
@@ -32,7 +32,7 @@ func TestSqlGeneratorImpl_GenerateSelect(t *testing.T) {
     }
 
     expected := "SELECT col_1, col_2 FROM any_table WHERE id_col = ?"
-    actual, _ := sqlGen.GenerateSelect("any_table", "id_col", []string{"col_1", "col_2"})
+    actual, _ := sqlGen.GenerateSelect("any_table", []string{"id_col"}, []string{"col_1", "col_2"})
 
     if actual != expected {
         t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
@@ -46,7 +46,7 @@ func TestSqlGeneratorImpl_GenerateSelect_GetParamPlaceholdersErr(t *testing.T) {
         paramGen: sqlParamGenMock,
     }
 
-    _, actual := sqlGen.GenerateSelect("any_table", "id_col", []string{"col_1", "col_2"})
+    _, actual := sqlGen.GenerateSelect("any_table", []string{"id_col"}, []string{"col_1", "col_2"})
 
     if actual != expected {
         t.Fatalf("Expected %v but got %v", expected, actual)
@@ -92,14 +92,212 @@ func TestSqlGeneratorImpl_GenerateInsert_GetParamPlaceholdersErr(t *testing.T) {
     }
 }
 
+func TestSqlGeneratorImpl_GenerateInsertMany(t *testing.T) {
+    tests := map[Dialect]string{
+        MySQL:      "INSERT INTO Users (Name, Age) VALUES (?, ?), (?, ?)",
+        PostgreSQL: "INSERT INTO Users (Name, Age) VALUES ($1, $2), ($3, $4)",
+        SQLServer:  "INSERT INTO Users (Name, Age) VALUES (@p1, @p2), (@p3, @p4)",
+        Oracle:     "INSERT ALL INTO Users (Name, Age) VALUES (:val1, :val2) INTO Users (Name, Age) VALUES (:val3, :val4) SELECT 1 FROM DUAL",
+    }
+
+    for dialect, expected := range tests {
+        sqlGen := sqlGeneratorImpl{
+            paramGen: newSQLParamGen(dialect),
+            dialect:  dialect,
+        }
+
+        actual, _ := sqlGen.GenerateInsertMany("Users", []string{"Name", "Age"}, 2)
+
+        if actual != expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateInsertMany_UnsupportedSingleRow(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(ODBC),
+        dialect:  ODBC,
+    }
+
+    expected := "INSERT INTO Users (Name, Age) VALUES (?, ?)"
+    actual, _ := sqlGen.GenerateInsertMany("Users", []string{"Name", "Age"}, 1)
+
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateInsertMany_Unsupported(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(ODBC),
+        dialect:  ODBC,
+    }
+    expected := "dialect odbc cannot insert several rows in one statement"
+
+    _, actual := sqlGen.GenerateInsertMany("Users", []string{"Name"}, 2)
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateInsertReturning(t *testing.T) {
+    tests := map[Dialect]string{
+        MySQL:      "INSERT INTO Users (Name, Age) VALUES (?, ?)",
+        PostgreSQL: "INSERT INTO Users (Name, Age) VALUES ($1, $2) RETURNING Id, Name, Age",
+        SQLite:     "INSERT INTO Users (Name, Age) VALUES (?, ?) RETURNING Id, Name, Age",
+        SQLServer:  "INSERT INTO Users (Name, Age) OUTPUT INSERTED.Id, INSERTED.Name, INSERTED.Age VALUES (@p1, @p2)",
+        Oracle:     "INSERT INTO Users (Name, Age) VALUES (:val1, :val2) RETURNING Id INTO :col3",
+    }
+
+    for dialect, expected := range tests {
+        sqlGen := sqlGeneratorImpl{
+            paramGen: newSQLParamGen(dialect),
+            dialect:  dialect,
+        }
+
+        actual, _ := sqlGen.GenerateInsertReturning("Users", "Id", []string{"Name", "Age"}, []string{"Id", "Name", "Age"})
+
+        if actual != expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateInsertReturning_Unsupported(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(ODBC),
+        dialect:  ODBC,
+    }
+    expected := "dialect odbc cannot return generated values"
+
+    _, actual := sqlGen.GenerateInsertReturning("Users", "Id", []string{"Name"}, []string{"Id", "Name"})
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateInsertReturning_GetParamPlaceholdersErr(t *testing.T) {
+    expected := fmt.Errorf("AnyError")
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSqlParameterGeneratorMock(expected),
+        dialect:  PostgreSQL,
+    }
+
+    _, actual := sqlGen.GenerateInsertReturning("", "", []string{}, []string{})
+
+    if actual != expected {
+        t.Fatalf("Expected %v but got %v", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpsert(t *testing.T) {
+    tests := map[Dialect]string{
+        PostgreSQL: "INSERT INTO Users (Email, Name) VALUES ($1, $2) ON CONFLICT (Email) DO UPDATE SET Name = EXCLUDED.Name",
+        MySQL:      "INSERT INTO Users (Email, Name) VALUES (?, ?) ON DUPLICATE KEY UPDATE Name = VALUES(Name)",
+        SQLServer: "MERGE INTO Users WITH (HOLDLOCK) AS target USING (SELECT @p1 AS Email, @p2 AS Name) AS source ON (target.Email = source.Email) " +
+            "WHEN MATCHED THEN UPDATE SET target.Name = source.Name " +
+            "WHEN NOT MATCHED THEN INSERT (Email, Name) VALUES (source.Email, source.Name);",
+        Oracle: "MERGE INTO Users target USING (SELECT :val1 AS Email, :val2 AS Name FROM DUAL) source ON (target.Email = source.Email) " +
+            "WHEN MATCHED THEN UPDATE SET target.Name = source.Name " +
+            "WHEN NOT MATCHED THEN INSERT (Email, Name) VALUES (source.Email, source.Name)",
+    }
+
+    for dialect, expected := range tests {
+        sqlGen := sqlGeneratorImpl{
+            paramGen: newSQLParamGen(dialect),
+            dialect:  dialect,
+        }
+
+        actual, _ := sqlGen.GenerateUpsert("Users", []string{"Email", "Name"}, []string{"Email"}, []string{"Name"}, nil)
+
+        if actual != expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpsert_NothingToUpdate(t *testing.T) {
+    tests := map[Dialect]string{
+        SQLite: "INSERT INTO Tags (Name) VALUES (?) ON CONFLICT (Name) DO NOTHING",
+        MySQL:  "INSERT INTO Tags (Name) VALUES (?) ON DUPLICATE KEY UPDATE Name = Name",
+    }
+
+    for dialect, expected := range tests {
+        sqlGen := sqlGeneratorImpl{
+            paramGen: newSQLParamGen(dialect),
+            dialect:  dialect,
+        }
+
+        actual, _ := sqlGen.GenerateUpsert("Tags", []string{"Name"}, []string{"Name"}, nil, nil)
+
+        if actual != expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpsert_Increments(t *testing.T) {
+    tests := map[Dialect]string{
+        PostgreSQL: "INSERT INTO Docs (Id, Title) VALUES ($1, $2) ON CONFLICT (Id) DO UPDATE SET Title = EXCLUDED.Title, Version = Docs.Version + 1",
+        MySQL:      "INSERT INTO Docs (Id, Title) VALUES (?, ?) ON DUPLICATE KEY UPDATE Title = VALUES(Title), Version = Version + 1",
+        SQLServer: "MERGE INTO Docs WITH (HOLDLOCK) AS target USING (SELECT @p1 AS Id, @p2 AS Title) AS source ON (target.Id = source.Id) " +
+            "WHEN MATCHED THEN UPDATE SET target.Title = source.Title, target.Version = target.Version + 1 " +
+            "WHEN NOT MATCHED THEN INSERT (Id, Title) VALUES (source.Id, source.Title);",
+    }
+
+    for dialect, expected := range tests {
+        sqlGen := sqlGeneratorImpl{
+            paramGen: newSQLParamGen(dialect),
+            dialect:  dialect,
+        }
+
+        actual, _ := sqlGen.GenerateUpsert("Docs", []string{"Id", "Title"}, []string{"Id"}, []string{"Title"}, []string{"Version"})
+
+        if actual != expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpsert_Unsupported(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(ODBC),
+        dialect:  ODBC,
+    }
+    expected := "dialect odbc does not support upserts"
+
+    _, actual := sqlGen.GenerateUpsert("Users", []string{"Id"}, []string{"Id"}, nil, nil)
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
 func TestSqlGeneratorImpl_GenerateUpdate(t *testing.T) {
     sqlParamGenMock := newSqlParameterGeneratorMock(nil)
     sqlGen := sqlGeneratorImpl{
         paramGen: sqlParamGenMock,
     }
 
-    expected := "UPDATE any_table SET (col_1 = ?, col_2 = ?) WHERE id_col = ?"
-    actual, _ := sqlGen.GenerateUpdate("any_table", "id_col", []string{"col_1", "col_2"})
+    expected := "UPDATE any_table SET col_1 = ?, col_2 = ? WHERE id_col = ?"
+    actual, _ := sqlGen.GenerateUpdate("any_table", []string{"id_col"}, []string{"col_1", "col_2"}, nil)
+
+    if actual != expected {
+        t.Fatalf("Expected %v but got %v", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpdate_Increments(t *testing.T) {
+    sqlParamGenMock := newSqlParameterGeneratorMock(nil)
+    sqlGen := sqlGeneratorImpl{
+        paramGen: sqlParamGenMock,
+    }
+
+    expected := "UPDATE any_table SET col_1 = ?, version = version + 1 WHERE id_col = ?"
+    actual, _ := sqlGen.GenerateUpdate("any_table", []string{"id_col"}, []string{"col_1"}, []string{"version"})
 
     if actual != expected {
         t.Fatalf("Expected %v but got %v", expected, actual)
@@ -113,7 +311,7 @@ func TestSqlGeneratorImpl_GenerateUpdate_GetColumnsParamPlaceholdersErr(t *testi
         paramGen: sqlParamGenMock,
     }
 
-    _, actual := sqlGen.GenerateUpdate("", "", []string{})
+    _, actual := sqlGen.GenerateUpdate("", []string{""}, []string{}, nil)
 
     if actual != expected {
         t.Fatalf("Expected %v but got %v", expected, actual)
@@ -123,18 +321,18 @@ func TestSqlGeneratorImpl_GenerateUpdate_GetColumnsParamPlaceholdersErr(t *testi
 func TestSqlGeneratorImpl_GenerateUpdate_GetValuesParamPlaceholdersErr(t *testing.T) {
     expected := fmt.Errorf("AnyError")
     sqlParamGenMock := sqlParameterGeneratorMock{
-        GetParamPlaceholdersMock: func(amount int, typ parameterType) ([]string, error) {
+        GetParamPlaceholdersMock: func(offset int, amount int, typ ParameterType) ([]string, error) {
             if typ == Values {
                 return nil, expected
             }
-            return []string{}, nil
+            return make([]string, amount), nil
         },
     }
     sqlGen := sqlGeneratorImpl{
         paramGen: sqlParamGenMock,
     }
 
-    _, actual := sqlGen.GenerateUpdate("", "", []string{})
+    _, actual := sqlGen.GenerateUpdate("", []string{""}, []string{}, nil)
 
     if actual != expected {
         t.Fatalf("Expected %v but got %v", expected, actual)
@@ -148,7 +346,7 @@ func TestSqlGeneratorImpl_GenerateDelete(t *testing.T) {
     }
 
     expected := "DELETE FROM any_table WHERE id_col = ?"
-    actual, _ := sqlGen.GenerateDelete("any_table", "id_col")
+    actual, _ := sqlGen.GenerateDelete("any_table", []string{"id_col"})
 
     if actual != expected {
         t.Fatalf("Expected %v but got %v", expected, actual)
@@ -162,22 +360,469 @@ func TestSqlGeneratorImpl_GenerateDelete_GetParamPlaceholdersErr(t *testing.T) {
         paramGen: sqlParamGenMock,
     }
 
-    _, actual := sqlGen.GenerateDelete("", "")
+    _, actual := sqlGen.GenerateDelete("", []string{""})
 
     if actual != expected {
         t.Fatalf("Expected %v but got %v", expected, actual)
     }
 }
 
+func TestSqlGeneratorImpl_GenerateUpdate_SQLServer(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(SQLServer),
+    }
+
+    expected := "UPDATE any_table SET col_1 = @p1, col_2 = @p2 WHERE id_col = @p3"
+    actual, _ := sqlGen.GenerateUpdate("any_table", []string{"id_col"}, []string{"col_1", "col_2"}, nil)
+
+    if actual != expected {
+        t.Fatalf("Expected %v but got %v", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpdate_DoesNotModifyFields(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSqlParameterGeneratorMock(nil),
+    }
+    fields := []string{"col_1", "col_2"}
+
+    _, _ = sqlGen.GenerateUpdate("any_table", []string{"id_col"}, fields, nil)
+
+    if !reflect.DeepEqual(fields, []string{"col_1", "col_2"}) {
+        t.Fatalf("Expected fields to be left untouched but got %v", fields)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelect_Quoted(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(PostgreSQL),
+        quoter:   newSQLIdentifierQuoter(PostgreSQL, QuoteAlways),
+    }
+
+    expected := "SELECT \"Id\", \"order\" FROM \"app\".\"Orders\" WHERE \"Id\" = $1"
+    actual, _ := sqlGen.GenerateSelect("app.Orders", []string{"Id"}, []string{"Id", "order"})
+
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpdate_QuotedReserved(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(MySQL),
+        quoter:   newSQLIdentifierQuoter(MySQL, QuoteReserved),
+    }
+
+    expected := "UPDATE `user` SET `group` = ?, name = ? WHERE id = ?"
+    actual, _ := sqlGen.GenerateUpdate("user", []string{"id"}, []string{"group", "name"}, nil)
+
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateInsert_QuotedSQLServer(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(SQLServer),
+        quoter:   newSQLIdentifierQuoter(SQLServer, QuoteAlways),
+    }
+
+    expected := "INSERT INTO [Users] ([Name], [Order]) VALUES (@p1, @p2)"
+    actual, _ := sqlGen.GenerateInsert("Users", []string{"Name", "Order"})
+
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+}
+
 func TestNewSqlGenerator(t *testing.T) {
     sqlParamGenMock := sqlParameterGeneratorMock{}
+    quoter := newSQLIdentifierQuoter(MySQL, QuoteNever)
 
     expected := &sqlGeneratorImpl{
         paramGen: sqlParamGenMock,
+        quoter:   quoter,
+        dialect:  MySQL,
     }
-    actual := newSQLGenerator(sqlParamGenMock)
+    actual := newSQLGenerator(sqlParamGenMock, quoter, MySQL)
 
     if !reflect.DeepEqual(expected, actual) {
         t.Fatalf("\nExpected %v\nbut got %v", expected, actual)
     }
 }
+
+func TestSqlGeneratorImpl_GenerateSelectWhere(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+    tests := map[string]string{
+        "":          "SELECT col_1, col_2 FROM any_table",
+        "col_1 = ?": "SELECT col_1, col_2 FROM any_table WHERE col_1 = ?",
+    }
+
+    for where, expected := range tests {
+        actual := sqlGen.GenerateSelectWhere("any_table", []string{"col_1", "col_2"}, where)
+        if actual != expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateCount(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+    tests := map[string]string{
+        "":          "SELECT COUNT(*) FROM any_table",
+        "col_1 = ?": "SELECT COUNT(*) FROM any_table WHERE col_1 = ?",
+    }
+
+    for where, expected := range tests {
+        actual := sqlGen.GenerateCount("any_table", where)
+        if actual != expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateDeleteWhere(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+
+    expected := "DELETE FROM any_table WHERE col_1 = ?"
+    actual, _ := sqlGen.GenerateDeleteWhere("any_table", "col_1 = ?")
+
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateDeleteWhere_Empty(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+    expected := "DELETE statement requires a condition"
+
+    _, actual := sqlGen.GenerateDeleteWhere("any_table", "")
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateOrderBy(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+
+    expected := "col_1, col_2 DESC"
+    actual, _ := sqlGen.GenerateOrderBy([]OrderBy{Asc("col_1"), Desc("col_2")}, []string{"col_1", "col_2"})
+
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateOrderBy_UnknownColumn(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+    expected := "unknown column \"col_1; --\" in order by"
+
+    _, actual := sqlGen.GenerateOrderBy([]OrderBy{Asc("col_1; --")}, []string{"col_1"})
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelectPage(t *testing.T) {
+    tests := map[Dialect]string{
+        MySQL:     "SELECT col_1, col_2 FROM any_table WHERE col_1 = ? ORDER BY col_1 LIMIT 10 OFFSET 20",
+        SQLServer: "SELECT col_1, col_2 FROM any_table WHERE col_1 = ? ORDER BY col_1 OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+        Oracle:    "SELECT col_1, col_2 FROM any_table WHERE col_1 = ? ORDER BY col_1 OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+        Oracle11g: "SELECT col_1, col_2 FROM (SELECT page.*, ROWNUM page_rownum FROM " +
+            "(SELECT col_1, col_2 FROM any_table WHERE col_1 = ? ORDER BY col_1) page WHERE ROWNUM <= 30) WHERE page_rownum > 20",
+    }
+
+    for dialect, expected := range tests {
+        sqlGen := sqlGeneratorImpl{
+            dialect: dialect,
+        }
+
+        actual, err := sqlGen.GenerateSelectPage("any_table", []string{"col_1", "col_2"}, "col_1 = ?", "col_1", 10, 20)
+
+        if err != nil || actual != expected {
+            t.Fatalf("Expected \"%s\" for %s but got \"%s\" (%v)", expected, dialect.Name(), actual, err)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelectPage_InvalidPage(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        dialect: MySQL,
+    }
+    tests := map[string][2]int{
+        "limit must be at least 1":  {0, 0},
+        "offset cannot be negative": {10, -1},
+    }
+
+    for expected, page := range tests {
+        _, actual := sqlGen.GenerateSelectPage("any_table", []string{"col_1"}, "", "col_1", page[0], page[1])
+        if actual == nil || actual.Error() != expected {
+            t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelectPage_Unsupported(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        dialect: ODBC,
+    }
+    expected := "dialect odbc does not support pagination"
+
+    _, actual := sqlGen.GenerateSelectPage("any_table", []string{"col_1"}, "", "col_1", 10, 0)
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateAggregate(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+    tests := []struct {
+        fn       AggregateFunc
+        column   string
+        groupBy  string
+        where    string
+        expected string
+    }{
+        {AggregateCount, "", "", "", "SELECT COUNT(*) FROM any_table"},
+        {AggregateCount, "col_1", "", "", "SELECT COUNT(col_1) FROM any_table"},
+        {AggregateSum, "col_1", "", "col_2 = ?", "SELECT SUM(col_1) FROM any_table WHERE col_2 = ?"},
+        {AggregateMin, "col_1", "", "", "SELECT MIN(col_1) FROM any_table"},
+        {AggregateMax, "col_1", "", "", "SELECT MAX(col_1) FROM any_table"},
+        {AggregateAvg, "col_1", "col_2", "col_1 > ?", "SELECT col_2, AVG(col_1) FROM any_table WHERE col_1 > ? GROUP BY col_2"},
+        {AggregateCount, "", "col_2", "", "SELECT col_2, COUNT(*) FROM any_table GROUP BY col_2"},
+    }
+
+    for _, test := range tests {
+        actual, err := sqlGen.GenerateAggregate("any_table", test.fn, test.column, test.groupBy, test.where)
+        if err != nil || actual != test.expected {
+            t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", test.expected, actual, err)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateAggregate_Invalid(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{}
+    tests := map[string]AggregateFunc{
+        "SUM requires a column":        AggregateSum,
+        "unknown aggregate function 9": 9,
+    }
+
+    for expected, fn := range tests {
+        _, actual := sqlGen.GenerateAggregate("any_table", fn, "", "", "")
+        if actual == nil || actual.Error() != expected {
+            t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_CompositeKey(t *testing.T) {
+    idFields := []string{"user_id", "group_id"}
+    tests := map[Dialect]string{
+        MySQL:      "UPDATE memberships SET role = ? WHERE user_id = ? AND group_id = ?",
+        PostgreSQL: "UPDATE memberships SET role = $1 WHERE user_id = $2 AND group_id = $3",
+        SQLServer:  "UPDATE memberships SET role = @p1 WHERE user_id = @p2 AND group_id = @p3",
+        Oracle:     "UPDATE memberships SET role = :val WHERE user_id = :col2 AND group_id = :col3",
+    }
+
+    for dialect, expected := range tests {
+        sqlGen := sqlGeneratorImpl{
+            paramGen: newSQLParamGen(dialect),
+        }
+
+        actual, err := sqlGen.GenerateUpdate("memberships", idFields, []string{"role"}, nil)
+        if err != nil || actual != expected {
+            t.Fatalf("Expected \"%s\" for %s but got \"%s\" (%v)", expected, dialect.Name(), actual, err)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_CompositeKey_SelectDelete(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(Oracle),
+    }
+    idFields := []string{"user_id", "group_id"}
+
+    expected := "SELECT user_id, group_id FROM memberships WHERE user_id = :col1 AND group_id = :col2"
+    actual, _ := sqlGen.GenerateSelect("memberships", idFields, idFields)
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+
+    expected = "DELETE FROM memberships WHERE user_id = :col1 AND group_id = :col2"
+    actual, _ = sqlGen.GenerateDelete("memberships", idFields)
+    if actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_NoIDFields(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(MySQL),
+    }
+    expected := "at least one ID field is required"
+
+    _, actual := sqlGen.GenerateDelete("memberships", nil)
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelectForUpdate(t *testing.T) {
+    tests := []struct {
+        dialect  Dialect
+        lock     rowLock
+        expected string
+    }{
+        {PostgreSQL, rowLock{}, "SELECT col_1 FROM any_table WHERE col_1 = ? FOR UPDATE"},
+        {PostgreSQL, rowLock{shared: true, noWait: true}, "SELECT col_1 FROM any_table WHERE col_1 = ? FOR SHARE NOWAIT"},
+        {MySQL, rowLock{skipLocked: true}, "SELECT col_1 FROM any_table WHERE col_1 = ? FOR UPDATE SKIP LOCKED"},
+        {MariaDB, rowLock{shared: true}, "SELECT col_1 FROM any_table WHERE col_1 = ? LOCK IN SHARE MODE"},
+        {Oracle, rowLock{noWait: true}, "SELECT col_1 FROM any_table WHERE col_1 = ? FOR UPDATE NOWAIT"},
+        {SQLServer, rowLock{}, "SELECT col_1 FROM any_table WITH (UPDLOCK, ROWLOCK) WHERE col_1 = ?"},
+        {SQLServer, rowLock{skipLocked: true}, "SELECT col_1 FROM any_table WITH (UPDLOCK, ROWLOCK, READPAST) WHERE col_1 = ?"},
+        {SQLServer, rowLock{shared: true, noWait: true}, "SELECT col_1 FROM any_table WITH (REPEATABLEREAD, ROWLOCK, NOWAIT) WHERE col_1 = ?"},
+        {SQLServer, rowLock{shared: true, skipLocked: true}, "SELECT col_1 FROM any_table WITH (REPEATABLEREAD, ROWLOCK, READPAST) WHERE col_1 = ?"},
+    }
+
+    for _, test := range tests {
+        sqlGen := sqlGeneratorImpl{
+            dialect: test.dialect,
+        }
+
+        actual, err := sqlGen.GenerateSelectForUpdate("any_table", []string{"col_1"}, "col_1 = ?", "", 0, test.lock)
+        if err != nil || actual != test.expected {
+            t.Fatalf("Expected \"%s\" for %s but got \"%s\" (%v)", test.expected, test.dialect.Name(), actual, err)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelectForUpdate_Unsupported(t *testing.T) {
+    tests := map[string]Dialect{
+        "dialect sqlite cannot lock rows":             SQLite,
+        "dialect oracle cannot take shared row locks": Oracle,
+    }
+
+    for expected, dialect := range tests {
+        sqlGen := sqlGeneratorImpl{
+            dialect: dialect,
+        }
+
+        _, actual := sqlGen.GenerateSelectForUpdate("any_table", []string{"col_1"}, "", "", 0, rowLock{shared: true})
+        if actual == nil || actual.Error() != expected {
+            t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelectForUpdate_Limit(t *testing.T) {
+    tests := []struct {
+        dialect  Dialect
+        expected string
+    }{
+        {PostgreSQL, "SELECT col_1 FROM any_table WHERE col_1 = ? ORDER BY col_1 LIMIT 10 FOR UPDATE SKIP LOCKED"},
+        {MariaDB, "SELECT col_1 FROM any_table WHERE col_1 = ? ORDER BY col_1 LIMIT 10 FOR UPDATE SKIP LOCKED"},
+        {SQLServer, "SELECT TOP (10) col_1 FROM any_table WITH (UPDLOCK, ROWLOCK, READPAST) WHERE col_1 = ? ORDER BY col_1"},
+    }
+
+    for _, test := range tests {
+        sqlGen := sqlGeneratorImpl{
+            dialect: test.dialect,
+        }
+
+        actual, err := sqlGen.GenerateSelectForUpdate("any_table", []string{"col_1"}, "col_1 = ?", "col_1", 10, rowLock{skipLocked: true})
+        if err != nil || actual != test.expected {
+            t.Fatalf("Expected \"%s\" for %s but got \"%s\" (%v)", test.expected, test.dialect.Name(), actual, err)
+        }
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateSelectForUpdate_LimitUnsupported(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        dialect: Oracle,
+    }
+    expected := "dialect oracle cannot limit locked rows"
+
+    _, actual := sqlGen.GenerateSelectForUpdate("any_table", []string{"col_1"}, "", "col_1", 10, rowLock{skipLocked: true})
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpdateWhere(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(PostgreSQL),
+        dialect:  PostgreSQL,
+    }
+    expected := "UPDATE jobs SET status = $1 WHERE attempts >= $2"
+
+    actual, err := sqlGen.GenerateUpdateWhere("jobs", []string{"status"}, nil, "attempts >= $2")
+
+    if err != nil || actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", expected, actual, err)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateUpdateWhere_NoCondition(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(PostgreSQL),
+        dialect:  PostgreSQL,
+    }
+    expected := "UPDATE statement requires a condition"
+
+    _, actual := sqlGen.GenerateUpdateWhere("jobs", []string{"status"}, nil, "")
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateClaim(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(SQLite),
+        dialect:  SQLite,
+    }
+    columns := queueColumns{status: "status", attempts: "attempts", visibleAt: "visible_at"}
+    expected := "UPDATE jobs SET status = ?, visible_at = ?, attempts = attempts + 1 " +
+        "WHERE id IN (SELECT id FROM jobs WHERE status = ? ORDER BY id LIMIT 5) RETURNING id, status"
+
+    actual, err := sqlGen.GenerateClaim("jobs", []string{"id"}, []string{"id", "status"}, nil, columns, "status = ?", "id", 5)
+
+    if err != nil || actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", expected, actual, err)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateClaim_CompositeKey(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(SQLite),
+        dialect:  SQLite,
+    }
+    columns := queueColumns{status: "status", attempts: "attempts", visibleAt: "visible_at"}
+    expected := "UPDATE jobs SET status = ?, visible_at = ?, attempts = attempts + 1 " +
+        "WHERE (queue, id) IN (SELECT queue, id FROM jobs ORDER BY queue, id LIMIT 5) RETURNING queue, id"
+
+    actual, err := sqlGen.GenerateClaim("jobs", []string{"queue", "id"}, []string{"queue", "id"}, nil, columns, "", "queue, id", 5)
+
+    if err != nil || actual != expected {
+        t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", expected, actual, err)
+    }
+}
+
+func TestSqlGeneratorImpl_GenerateClaim_Unsupported(t *testing.T) {
+    sqlGen := sqlGeneratorImpl{
+        paramGen: newSQLParamGen(MySQL),
+        dialect:  MySQL,
+    }
+    expected := "dialect mysql cannot claim rows with UPDATE ... RETURNING"
+
+    _, actual := sqlGen.GenerateClaim("jobs", []string{"id"}, []string{"id"}, nil, queueColumns{}, "", "id", 5)
+
+    if actual == nil || actual.Error() != expected {
+        t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+    }
+}
@@ -0,0 +1,63 @@
+package dvbcrud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxIdentifierLength is the longest identifier accepted by any of the built-in dialects.
+const maxIdentifierLength = 128
+
+// InvalidIdentifierError is returned when a table or column name is not a valid identifier.
+// Identifiers must start with a letter or underscore, followed by letters, digits or underscores.
+type InvalidIdentifierError struct {
+	Identifier string
+	Reason     string
+}
+
+func (e *InvalidIdentifierError) Error() string {
+	return fmt.Sprintf("invalid identifier %q: %s", e.Identifier, e.Reason)
+}
+
+// validateIdentifier returns an *InvalidIdentifierError if identifier is not a plain identifier.
+func validateIdentifier(identifier string) error {
+	if identifier == "" {
+		return &InvalidIdentifierError{Identifier: identifier, Reason: "cannot be empty"}
+	}
+	if len(identifier) > maxIdentifierLength {
+		return &InvalidIdentifierError{
+			Identifier: identifier,
+			Reason:     fmt.Sprintf("longer than %d characters", maxIdentifierLength),
+		}
+	}
+
+	for i, r := range identifier {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		isDigit := r >= '0' && r <= '9'
+
+		if i == 0 && !isLetter {
+			return &InvalidIdentifierError{Identifier: identifier, Reason: "must start with a letter or underscore"}
+		}
+		if !isLetter && !isDigit {
+			return &InvalidIdentifierError{Identifier: identifier, Reason: fmt.Sprintf("contains illegal character %q", r)}
+		}
+	}
+
+	return nil
+}
+
+// validateTableName validates table, which may be qualified with a schema (e.g. schema.table).
+func validateTableName(table string) error {
+	parts := strings.Split(table, ".")
+	if len(parts) > 2 {
+		return &InvalidIdentifierError{Identifier: table, Reason: "only one schema qualifier is allowed"}
+	}
+
+	for _, part := range parts {
+		if err := validateIdentifier(part); err != nil {
+			return &InvalidIdentifierError{Identifier: table, Reason: err.(*InvalidIdentifierError).Reason}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package dvbcrud
+
+import "strings"
+
+// QuotingMode controls when identifiers are quoted in generated statements.
+type QuotingMode int
+
+const (
+	// QuoteNever leaves identifiers as they are.
+	QuoteNever QuotingMode = iota
+	// QuoteAlways quotes every identifier.
+	QuoteAlways
+	// QuoteReserved only quotes identifiers that are reserved words, or that the dialect
+	// would fold to lower case, such as UserId on PostgreSQL.
+	QuoteReserved
+)
+
+type sqlIdentifierQuoter interface {
+	// Quote returns identifier quoted according to the dialect and quoting mode.
+	// Qualified identifiers (e.g. schema.table) have each part quoted separately.
+	Quote(identifier string) string
+}
+
+type sqlIdentifierQuoterImpl struct {
+	sqlIdentifierQuoter
+	dialect Dialect
+	mode    QuotingMode
+}
+
+func (q sqlIdentifierQuoterImpl) Quote(identifier string) string {
+	if q.mode == QuoteNever {
+		return identifier
+	}
+
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		if q.mode == QuoteAlways || isReservedWord(part) || q.isFolded(part) {
+			parts[i] = q.dialect.QuoteIdentifier(part)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// isFolded reports whether the dialect would fold identifier to lower case if it was left unquoted.
+// Upper-case folding is left alone, since lower-case names are commonly used for Oracle columns
+// and rely on it to match their upper-case names.
+func (q sqlIdentifierQuoterImpl) isFolded(identifier string) bool {
+	return q.dialect.IdentifierCase() == IdentifierCaseLower && identifier != strings.ToLower(identifier)
+}
+
+func newSQLIdentifierQuoter(dialect Dialect, mode QuotingMode) sqlIdentifierQuoter {
+	return sqlIdentifierQuoterImpl{
+		dialect: dialect,
+		mode:    mode,
+	}
+}
+
+// isReservedWord reports whether word is reserved in any of the built-in dialects.
+func isReservedWord(word string) bool {
+	_, ok := reservedWords[strings.ToUpper(word)]
+	return ok
+}
+
+// reservedWords holds words that are reserved in at least one of the built-in dialects
+// and are likely to clash with table or column names.
+var reservedWords = map[string]struct{}{
+	"ACCESS": {}, "ADD": {}, "ALL": {}, "ALTER": {}, "AND": {}, "ANY": {}, "AS": {}, "ASC": {},
+	"AUDIT": {}, "BETWEEN": {}, "BY": {}, "CASE": {}, "CAST": {}, "CHECK": {}, "COLUMN": {},
+	"COMMENT": {}, "CONSTRAINT": {}, "CREATE": {}, "CROSS": {}, "CURRENT": {}, "CURRENT_DATE": {},
+	"CURRENT_TIME": {}, "CURRENT_TIMESTAMP": {}, "CURRENT_USER": {}, "DATABASE": {}, "DATE": {},
+	"DEFAULT": {}, "DELETE": {}, "DESC": {}, "DISTINCT": {}, "DROP": {}, "ELSE": {}, "END": {},
+	"EXCEPT": {}, "EXISTS": {}, "FALSE": {}, "FETCH": {}, "FILE": {}, "FOR": {}, "FOREIGN": {},
+	"FROM": {}, "FULL": {}, "FUNCTION": {}, "GRANT": {}, "GROUP": {}, "HAVING": {}, "IDENTITY": {},
+	"IN": {}, "INDEX": {}, "INNER": {}, "INSERT": {}, "INTERSECT": {}, "INTO": {}, "IS": {},
+	"JOIN": {}, "KEY": {}, "LEFT": {}, "LEVEL": {}, "LIKE": {}, "LIMIT": {}, "LOCK": {}, "MERGE": {},
+	"MINUS": {}, "MODE": {}, "NATURAL": {}, "NOT": {}, "NULL": {}, "NUMBER": {}, "OF": {},
+	"OFFSET": {}, "ON": {}, "OPTION": {}, "OR": {}, "ORDER": {}, "OUTER": {}, "PLAN": {},
+	"PRIMARY": {}, "PROCEDURE": {}, "PUBLIC": {}, "RANGE": {}, "REFERENCES": {}, "RETURNING": {},
+	"RIGHT": {}, "ROW": {}, "ROWNUM": {}, "ROWS": {}, "SCHEMA": {}, "SELECT": {}, "SESSION": {},
+	"SET": {}, "SIZE": {}, "SOME": {}, "START": {}, "SYSDATE": {}, "TABLE": {}, "THEN": {}, "TO": {},
+	"TOP": {}, "TRIGGER": {}, "TRUE": {}, "UNION": {}, "UNIQUE": {}, "UPDATE": {}, "USER": {},
+	"USING": {}, "VALUE": {}, "VALUES": {}, "VIEW": {}, "WHEN": {}, "WHERE": {}, "WINDOW": {},
+	"WITH": {},
+}
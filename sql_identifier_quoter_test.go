@@ -0,0 +1,84 @@
+package dvbcrud
+
+import "testing"
+
+func TestSqlIdentifierQuoterImpl_Quote_Never(t *testing.T) {
+	quoter := newSQLIdentifierQuoter(PostgreSQL, QuoteNever)
+	expected := "order"
+
+	actual := quoter.Quote("order")
+
+	if actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+	}
+}
+
+func TestSqlIdentifierQuoterImpl_Quote_Always(t *testing.T) {
+	quoter := newSQLIdentifierQuoter(PostgreSQL, QuoteAlways)
+	expected := "\"UserId\""
+
+	actual := quoter.Quote("UserId")
+
+	if actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+	}
+}
+
+func TestSqlIdentifierQuoterImpl_Quote_Qualified(t *testing.T) {
+	quoter := newSQLIdentifierQuoter(SQLServer, QuoteAlways)
+	expected := "[dbo].[Users]"
+
+	actual := quoter.Quote("dbo.Users")
+
+	if actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+	}
+}
+
+func TestSqlIdentifierQuoterImpl_Quote_Reserved(t *testing.T) {
+	quoter := newSQLIdentifierQuoter(MySQL, QuoteReserved)
+	tests := map[string]string{
+		"order":      "`order`",
+		"Group":      "`Group`",
+		"user":       "`user`",
+		"name":       "name",
+		"app.order":  "app.`order`",
+		"created_at": "created_at",
+	}
+
+	for identifier, expected := range tests {
+		actual := quoter.Quote(identifier)
+		if actual != expected {
+			t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+		}
+	}
+}
+
+func TestSqlIdentifierQuoterImpl_Quote_ReservedFolded(t *testing.T) {
+	quoter := newSQLIdentifierQuoter(PostgreSQL, QuoteReserved)
+	tests := map[string]string{
+		"UserId":     "\"UserId\"",
+		"user_id":    "user_id",
+		"order":      "\"order\"",
+		"app.Users":  "app.\"Users\"",
+		"created_at": "created_at",
+	}
+
+	for identifier, expected := range tests {
+		actual := quoter.Quote(identifier)
+		if actual != expected {
+			t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+		}
+	}
+}
+
+func TestSqlIdentifierQuoterImpl_Quote_ReservedUpperCaseFolding(t *testing.T) {
+	quoter := newSQLIdentifierQuoter(Oracle, QuoteReserved)
+	expected := "UserId"
+
+	actual := quoter.Quote("UserId")
+
+	if actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+	}
+}
@@ -0,0 +1,55 @@
+package dvbcrud
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	for _, identifier := range []string{"id", "UserId", "_private", "created_at2"} {
+		if err := validateIdentifier(identifier); err != nil {
+			t.Fatalf("Expected \"%s\" to be valid, but got: %s", identifier, err)
+		}
+	}
+}
+
+func TestValidateIdentifier_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"":                       "invalid identifier \"\": cannot be empty",
+		"1st":                    "invalid identifier \"1st\": must start with a letter or underscore",
+		"id; DROP TABLE Users":   "invalid identifier \"id; DROP TABLE Users\": contains illegal character ';'",
+		"name\"":                 "invalid identifier \"name\\\"\": contains illegal character '\"'",
+		"app.Users":              "invalid identifier \"app.Users\": contains illegal character '.'",
+		strings.Repeat("a", 129): "invalid identifier \"" + strings.Repeat("a", 129) + "\": longer than 128 characters",
+	}
+
+	for identifier, expected := range tests {
+		err := validateIdentifier(identifier)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+		}
+	}
+}
+
+func TestValidateTableName(t *testing.T) {
+	for _, table := range []string{"Users", "app.Users"} {
+		if err := validateTableName(table); err != nil {
+			t.Fatalf("Expected \"%s\" to be valid, but got: %s", table, err)
+		}
+	}
+}
+
+func TestValidateTableName_Invalid(t *testing.T) {
+	for _, table := range []string{"db.app.Users", "app.", ".Users", "Users--"} {
+		err := validateTableName(table)
+
+		var identifierErr *InvalidIdentifierError
+		if !errors.As(err, &identifierErr) {
+			t.Fatalf("Expected InvalidIdentifierError for \"%s\" but got %v", table, err)
+		}
+		if identifierErr.Identifier != table {
+			t.Fatalf("Expected error to refer to \"%s\" but got \"%s\"", table, identifierErr.Identifier)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package dvbcrud
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Key holds the values of a composite primary key, in the order the ID fields were given to WithIDFields.
+type Key []any
+
+// keyValues returns the values of the ID columns in id. id is a Key, or a struct or pointer to a struct
+// whose db tags include the ID columns. With a single ID column, id may also be the value itself.
+func (r SQLRepository[T]) keyValues(id any) ([]any, error) {
+	if key, ok := id.(Key); ok {
+		if len(key) != len(r.idFields) {
+			return nil, fmt.Errorf("key has %d values but the table has %d ID columns", len(key), len(r.idFields))
+		}
+		return key, nil
+	}
+
+	if len(r.idFields) == 1 {
+		return []any{id}, nil
+	}
+
+	val := reflect.ValueOf(id)
+	if val.Kind() == reflect.Pointer && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("composite key must be a Key or a struct, but got %T", id)
+	}
+
+	fields, values, err := r.structParser.ParseProperties(val.Interface(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	keyValues := make([]any, len(r.idFields))
+	for i, idField := range r.idFields {
+		found := false
+		for j, field := range fields {
+			if field == idField {
+				keyValues[i] = values[j]
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%T has no field tagged \"%s\"", id, idField)
+		}
+	}
+
+	return keyValues, nil
+}
+
+// insertProperties returns the fields and values of model to insert. A single ID column is left out,
+// as the database generates it, while the columns of a composite key are inserted.
+func (r SQLRepository[T]) insertProperties(model any) ([]string, []any, error) {
+	if len(r.idFields) > 1 {
+		return r.structParser.ParseProperties(model, "")
+	}
+	return r.structParser.ParseProperties(model, r.idFields[0])
+}
+
+// nonKeyProperties returns the fields and values of model, except for the ID columns and
+// the soft delete column, which only Delete and Restore change.
+func (r SQLRepository[T]) nonKeyProperties(model any) ([]string, []any, error) {
+	fields, values, err := r.structParser.ParseProperties(model, r.idFields[0])
+	if err != nil || (len(r.idFields) == 1 && r.softDelete == "") {
+		return fields, values, err
+	}
+
+	nonKeyFields := make([]string, 0, len(fields))
+	nonKeyValues := make([]any, 0, len(values))
+	for i, field := range fields {
+		if !contains(r.idFields, field) && field != r.softDelete {
+			nonKeyFields = append(nonKeyFields, field)
+			nonKeyValues = append(nonKeyValues, values[i])
+		}
+	}
+
+	return nonKeyFields, nonKeyValues, nil
+}
+
+// keyFilter returns a filter that matches the row whose ID matches id.
+func (r SQLRepository[T]) keyFilter(id any) (Filter, error) {
+	keyValues, err := r.keyValues(id)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]Filter, len(r.idFields))
+	for i, idField := range r.idFields {
+		conditions[i] = Eq(idField, keyValues[i])
+	}
+	return And(conditions...), nil
+}
+
+// modelKey returns the Key of the row that model, a pointer to T, was read from.
+func (r SQLRepository[T]) modelKey(model *T) (Key, error) {
+	key := make(Key, len(r.idFields))
+	for i, idField := range r.idFields {
+		ptr, err := r.structParser.FieldPointer(model, idField)
+		if err != nil {
+			return nil, err
+		}
+		key[i] = reflect.ValueOf(ptr).Elem().Interface()
+	}
+	return key, nil
+}
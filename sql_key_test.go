@@ -0,0 +1,136 @@
+package dvbcrud
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+type membershipTest struct {
+	UserID  int    `db:"user_id"`
+	GroupID int    `db:"group_id"`
+	Role    string `db:"role"`
+}
+
+type membershipTestKey struct {
+	GroupID int `db:"group_id"`
+	UserID  int `db:"user_id"`
+}
+
+type membershipTestPartialKey struct {
+	UserID int `db:"user_id"`
+}
+
+var membershipTestOptions = []Option{WithTable("memberships"), WithIDFields("user_id", "group_id")}
+
+func TestSqlRepository_KeyValues(t *testing.T) {
+	repo, _, closeDB := newDialectMock[membershipTest](PostgreSQL, membershipTestOptions...)
+	defer closeDB()
+	expected := []any{1, 2}
+	ids := []any{
+		Key{1, 2},
+		membershipTestKey{UserID: 1, GroupID: 2},
+		&membershipTestKey{UserID: 1, GroupID: 2},
+		membershipTest{UserID: 1, GroupID: 2, Role: "AnyRole"},
+	}
+
+	for _, id := range ids {
+		actual, err := repo.keyValues(id)
+		if err != nil || !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Expected %v for %T but got %v (%v)", expected, id, actual, err)
+		}
+	}
+}
+
+func TestSqlRepository_KeyValues_Invalid(t *testing.T) {
+	repo, _, closeDB := newDialectMock[membershipTest](PostgreSQL, membershipTestOptions...)
+	defer closeDB()
+	tests := map[string]any{
+		"key has 1 values but the table has 2 ID columns":                   Key{1},
+		"composite key must be a Key or a struct, but got int":              1,
+		"dvbcrud.membershipTestPartialKey has no field tagged \"group_id\"": membershipTestPartialKey{UserID: 1},
+	}
+
+	for expected, id := range tests {
+		_, actual := repo.keyValues(id)
+		if actual == nil || actual.Error() != expected {
+			t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+		}
+	}
+}
+
+func TestSqlRepository_KeyValues_SingleID(t *testing.T) {
+	repo := SQLRepository[repoTestUser]{idFields: []string{"UserId"}}
+
+	actual, err := repo.keyValues(membershipTestKey{})
+
+	if err != nil || !reflect.DeepEqual(actual, []any{membershipTestKey{}}) {
+		t.Fatalf("Expected a single ID to be used as-is but got %v (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_CompositeKey(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[membershipTest](PostgreSQL, membershipTestOptions...)
+	defer closeDB()
+
+	mock.ExpectPrepare("INSERT INTO memberships (user_id, group_id, role) VALUES ($1, $2, $3)").
+		ExpectExec().
+		WithArgs(1, 2, "Owner").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("SELECT user_id, group_id, role FROM memberships WHERE user_id = $1 AND group_id = $2").
+		ExpectQuery().
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "group_id", "role"}).AddRow(1, 2, "Owner"))
+	mock.ExpectPrepare("UPDATE memberships SET role = $1 WHERE user_id = $2 AND group_id = $3").
+		ExpectExec().
+		WithArgs("Member", 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("DELETE FROM memberships WHERE user_id = $1 AND group_id = $2").
+		ExpectExec().
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.Create(membershipTest{UserID: 1, GroupID: 2, Role: "Owner"}); err != nil {
+		t.Fatalf("Expected Create to succeed, but got: %s", err)
+	}
+	if _, err := repo.Read(Key{1, 2}); err != nil {
+		t.Fatalf("Expected Read to succeed, but got: %s", err)
+	}
+	if err := repo.Update(membershipTestKey{UserID: 1, GroupID: 2}, &membershipTest{Role: "Member"}); err != nil {
+		t.Fatalf("Expected Update to succeed, but got: %s", err)
+	}
+	if err := repo.Delete(Key{1, 2}); err != nil {
+		t.Fatalf("Expected Delete to succeed, but got: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Expected all statements to run, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_CompositeKey_CreateReturning(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[membershipTest](MySQL, membershipTestOptions...)
+	defer closeDB()
+	model := membershipTest{UserID: 1, GroupID: 2, Role: "Owner"}
+
+	mock.ExpectPrepare("INSERT INTO memberships (user_id, group_id, role) VALUES (?, ?, ?)").
+		ExpectExec().
+		WithArgs(1, 2, "Owner").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	actual, err := repo.CreateReturning(model)
+	if err != nil || *actual != model {
+		t.Fatalf("Expected %v but got %v (%v)", model, actual, err)
+	}
+}
+
+func TestSqlRepository_CompositeKey_UpdateKeyColumn(t *testing.T) {
+	repo, _, closeDB := newDialectMock[membershipTest](MySQL, membershipTestOptions...)
+	defer closeDB()
+	expected := "cannot update ID column \"group_id\""
+
+	actual := repo.Patch(Key{1, 2}, map[string]any{"group_id": 3})
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
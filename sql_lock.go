@@ -0,0 +1,124 @@
+package dvbcrud
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// rowLock describes the locks taken on the rows read by ReadForUpdate and ReadWhereForUpdate.
+type rowLock struct {
+	shared     bool
+	noWait     bool
+	skipLocked bool
+}
+
+// LockOption configures the locks taken by ReadForUpdate and ReadWhereForUpdate.
+type LockOption func(lock *rowLock)
+
+// ForShare takes shared locks, which block writes but not other shared locks,
+// instead of exclusive locks.
+func ForShare() LockOption {
+	return func(lock *rowLock) {
+		lock.shared = true
+	}
+}
+
+// NoWait fails immediately instead of waiting when a row is already locked.
+func NoWait() LockOption {
+	return func(lock *rowLock) {
+		lock.noWait = true
+	}
+}
+
+// SkipLocked leaves out rows that are already locked instead of waiting for them.
+func SkipLocked() LockOption {
+	return func(lock *rowLock) {
+		lock.skipLocked = true
+	}
+}
+
+// newRowLock applies options to an exclusive lock that waits for locked rows.
+func newRowLock(options []LockOption) (rowLock, error) {
+	var lock rowLock
+	for _, option := range options {
+		if option != nil {
+			option(&lock)
+		}
+	}
+
+	if lock.noWait && lock.skipLocked {
+		return rowLock{}, fmt.Errorf("NoWait and SkipLocked cannot be combined")
+	}
+	return lock, nil
+}
+
+// ReadForUpdate fetches and locks the row whose ID matches id.
+func (r SQLRepository[T]) ReadForUpdate(id any, options ...LockOption) (*T, error) {
+	return r.ReadForUpdateContext(context.Background(), id, options...)
+}
+
+// ReadForUpdateContext fetches and locks the row whose ID matches id.
+// Like Read, it returns sql.ErrNoRows when there is no such row, or when it is locked and
+// SkipLocked is given. See ReadWhereForUpdateContext for how rows are locked.
+func (r SQLRepository[T]) ReadForUpdateContext(ctx context.Context, id any, options ...LockOption) (*T, error) {
+	filter, err := r.keyFilter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.ReadWhereForUpdateContext(ctx, filter, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &result[0], nil
+}
+
+// ReadWhereForUpdate fetches and locks all rows that match filter.
+func (r SQLRepository[T]) ReadWhereForUpdate(filter Filter, options ...LockOption) ([]T, error) {
+	return r.ReadWhereForUpdateContext(context.Background(), filter, options...)
+}
+
+// ReadWhereForUpdateContext fetches and locks all rows that match filter. A nil filter matches all rows.
+//
+// The rows are locked exclusively unless ForShare is given, and the call waits for rows locked by others
+// unless NoWait or SkipLocked is given. The locks are held until the transaction ends, so the repository
+// should run in one (see WithTx and RunInTx). SQL Server locks with table hints, Oracle cannot take shared
+// locks, and SQLite cannot lock rows at all.
+func (r SQLRepository[T]) ReadWhereForUpdateContext(ctx context.Context, filter Filter, options ...LockOption) ([]T, error) {
+	lock, err := newRowLock(options)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := templates.GetSelectForUpdate(filter, nil, 0, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var result []T
+	err = stmt.SelectContext(ctx, &result, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
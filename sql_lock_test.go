@@ -0,0 +1,88 @@
+package dvbcrud
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"testing"
+)
+
+func TestNewRowLock(t *testing.T) {
+	actual, err := newRowLock([]LockOption{ForShare(), SkipLocked(), nil})
+
+	if err != nil || actual != (rowLock{shared: true, skipLocked: true}) {
+		t.Fatalf("Expected a shared lock that skips locked rows but got %v (%v)", actual, err)
+	}
+}
+
+func TestNewRowLock_NoWaitAndSkipLocked(t *testing.T) {
+	expected := "NoWait and SkipLocked cannot be combined"
+
+	_, actual := newRowLock([]LockOption{NoWait(), SkipLocked()})
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_ReadForUpdate(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectForUpdateMock: func(filter Filter, orderBy []OrderBy, limit int, lock rowLock) (string, []any, error) {
+			if !lock.noWait || lock.shared {
+				t.Fatalf("Expected an exclusive NOWAIT lock but got %v", lock)
+			}
+			sqlGen := newSQLGenerator(newSQLParamGen(MySQL), nil, MySQL)
+			where, args, err := sqlGen.GenerateWhere(filter, repo.fields, 0)
+			if where != "UserId = ?" {
+				t.Fatalf("Expected the row to be selected by ID but got \"%s\" (%v)", where, err)
+			}
+			return "AnySelectForUpdate", args, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectForUpdate").
+		ExpectQuery().
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).AddRow(1, "AnyName"))
+
+	actual, err := repo.ReadForUpdate(1, NoWait())
+	if err != nil || actual.ID != 1 {
+		t.Fatalf("Expected user 1 but got %v (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_ReadForUpdate_NoRows(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectForUpdateMock: func(filter Filter, orderBy []OrderBy, limit int, lock rowLock) (string, []any, error) {
+			return "AnySelectForUpdate", []any{1}, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectForUpdate").
+		ExpectQuery().
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"UserId"}))
+
+	_, actual := repo.ReadForUpdate(1, SkipLocked())
+
+	if actual != sql.ErrNoRows {
+		t.Fatalf("Expected %v but got %v", sql.ErrNoRows, actual)
+	}
+}
+
+func TestSqlRepository_ReadWhereForUpdate_SQLite(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	sqlGen := newSQLGenerator(newSQLParamGen(SQLite), nil, SQLite)
+	repo.templates, _ = newSQLTemplates(sqlGen, "Users", []string{"UserId"}, repo.fields, "", "")
+	expected := "dialect sqlite cannot lock rows"
+
+	_, actual := repo.ReadWhereForUpdate(Eq("Name", "AnyName"))
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
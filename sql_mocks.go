@@ -4,25 +4,39 @@ import "reflect"
 
 type sqlParameterGeneratorMock struct {
 	sqlParameterGenerator
-	GetParamPlaceholdersMock func(amount int, typ parameterType) ([]string, error)
+	GetParamPlaceholdersMock func(offset int, amount int, typ ParameterType) ([]string, error)
 }
 
-func (p sqlParameterGeneratorMock) GetParamPlaceholders(amount int, typ parameterType) ([]string, error) {
-	return p.GetParamPlaceholdersMock(amount, typ)
+func (p sqlParameterGeneratorMock) GetParamPlaceholders(offset int, amount int, typ ParameterType) ([]string, error) {
+	return p.GetParamPlaceholdersMock(offset, amount, typ)
 }
 
 type sqlGeneratorMock struct {
 	sqlGenerator
 
-	generateSelectMock    func(table string, idField string, fields []string) (string, error)
-	generateSelectAllMock func(table string, fields []string) string
-	generateInsertMock    func(table string, fields []string) (string, error)
-	generateUpdateMock    func(table string, idField string, fields []string) (string, error)
-	generateDeleteMock    func(table string, idField string) (string, error)
+	generateSelectMock     func(table string, idFields []string, fields []string) (string, error)
+	generateSelectAllMock  func(table string, fields []string) string
+	generateInsertMock     func(table string, fields []string) (string, error)
+	generateInsertManyMock func(table string, fields []string, rows int) (string, error)
+	generateInsertRetMock  func(table string, idField string, fields []string, returnFields []string) (string, error)
+	generateUpsertMock     func(table string, fields []string, conflictFields []string, updateFields []string, increments []string) (string, error)
+	generateUpdateMock     func(table string, idFields []string, fields []string, increments []string) (string, error)
+	generateDeleteMock     func(table string, idFields []string) (string, error)
+
+	generateWhereMock           func(filter Filter, columns []string, offset int) (string, []any, error)
+	generateSelectWhereMock     func(table string, fields []string, where string) string
+	generateAggregateMock       func(table string, fn AggregateFunc, column string, groupBy string, where string) (string, error)
+	generateCountMock           func(table string, where string) string
+	generateSelectForUpdateMock func(table string, fields []string, where string, orderBy string, limit int, lock rowLock) (string, error)
+	generateOrderByMock         func(orderBy []OrderBy, columns []string) (string, error)
+	generateSelectPageMock      func(table string, fields []string, where string, orderBy string, limit int, offset int) (string, error)
+	generateDeleteWhereMock     func(table string, where string) (string, error)
+	generateUpdateWhereMock     func(table string, fields []string, increments []string, where string) (string, error)
+	generateClaimMock           func(table string, idFields []string, fields []string, increments []string, columns queueColumns, where string, orderBy string, limit int) (string, error)
 }
 
-func (s sqlGeneratorMock) GenerateSelect(table string, idField string, fields []string) (string, error) {
-	return s.generateSelectMock(table, idField, fields)
+func (s sqlGeneratorMock) GenerateSelect(table string, idFields []string, fields []string) (string, error) {
+	return s.generateSelectMock(table, idFields, fields)
 }
 
 func (s sqlGeneratorMock) GenerateSelectAll(table string, fields []string) string {
@@ -33,22 +47,92 @@ func (s sqlGeneratorMock) GenerateInsert(table string, fields []string) (string,
 	return s.generateInsertMock(table, fields)
 }
 
-func (s sqlGeneratorMock) GenerateUpdate(table string, idField string, fields []string) (string, error) {
-	return s.generateUpdateMock(table, idField, fields)
+func (s sqlGeneratorMock) GenerateInsertMany(table string, fields []string, rows int) (string, error) {
+	return s.generateInsertManyMock(table, fields, rows)
+}
+
+func (s sqlGeneratorMock) GenerateInsertReturning(table string, idField string, fields []string, returnFields []string) (string, error) {
+	return s.generateInsertRetMock(table, idField, fields, returnFields)
+}
+
+func (s sqlGeneratorMock) GenerateUpsert(table string, fields []string, conflictFields []string, updateFields []string, increments []string) (string, error) {
+	return s.generateUpsertMock(table, fields, conflictFields, updateFields, increments)
+}
+
+func (s sqlGeneratorMock) GenerateUpdate(table string, idFields []string, fields []string, increments []string) (string, error) {
+	return s.generateUpdateMock(table, idFields, fields, increments)
+}
+
+func (s sqlGeneratorMock) GenerateDelete(table string, idFields []string) (string, error) {
+	return s.generateDeleteMock(table, idFields)
+}
+
+func (s sqlGeneratorMock) GenerateWhere(filter Filter, columns []string, offset int) (string, []any, error) {
+	return s.generateWhereMock(filter, columns, offset)
+}
+
+func (s sqlGeneratorMock) GenerateSelectWhere(table string, fields []string, where string) string {
+	return s.generateSelectWhereMock(table, fields, where)
+}
+
+func (s sqlGeneratorMock) GenerateSelectForUpdate(table string, fields []string, where string, orderBy string, limit int, lock rowLock) (string, error) {
+	return s.generateSelectForUpdateMock(table, fields, where, orderBy, limit, lock)
 }
 
-func (s sqlGeneratorMock) GenerateDelete(table string, idField string) (string, error) {
-	return s.generateDeleteMock(table, idField)
+func (s sqlGeneratorMock) GenerateOrderBy(orderBy []OrderBy, columns []string) (string, error) {
+	return s.generateOrderByMock(orderBy, columns)
+}
+
+func (s sqlGeneratorMock) GenerateSelectPage(table string, fields []string, where string, orderBy string, limit int, offset int) (string, error) {
+	return s.generateSelectPageMock(table, fields, where, orderBy, limit, offset)
+}
+
+func (s sqlGeneratorMock) GenerateAggregate(table string, fn AggregateFunc, column string, groupBy string, where string) (string, error) {
+	return s.generateAggregateMock(table, fn, column, groupBy, where)
+}
+
+func (s sqlGeneratorMock) GenerateCount(table string, where string) string {
+	return s.generateCountMock(table, where)
+}
+
+func (s sqlGeneratorMock) GenerateDeleteWhere(table string, where string) (string, error) {
+	return s.generateDeleteWhereMock(table, where)
+}
+
+func (s sqlGeneratorMock) GenerateUpdateWhere(table string, fields []string, increments []string, where string) (string, error) {
+	return s.generateUpdateWhereMock(table, fields, increments, where)
+}
+
+func (s sqlGeneratorMock) GenerateClaim(table string, idFields []string, fields []string, increments []string, columns queueColumns, where string, orderBy string, limit int) (string, error) {
+	return s.generateClaimMock(table, idFields, fields, increments, columns, where, orderBy, limit)
 }
 
 type sqlTemplatesMock struct {
 	sqlTemplates
 
-	GetSelectMock    func() string
-	GetSelectAllMock func() string
-	GetInsertMock    func(fields []string) (string, error)
-	GetUpdateMock    func(fields []string) (string, error)
-	GetDeleteMock    func() string
+	GetSelectMock     func() string
+	GetSelectAllMock  func() string
+	GetInsertMock     func(fields []string) (string, error)
+	GetInsertManyMock func(fields []string, rows int) (string, error)
+	GetInsertRetMock  func(fields []string) (string, error)
+	GetUpsertMock     func(fields []string, conflictFields []string, updateFields []string) (string, error)
+	GetUpdateMock     func(fields []string) (string, error)
+	GetDeleteMock     func() string
+
+	GetSelectWithDeletedMock func() string
+	GetSoftDeleteMock        func() string
+	GetRestoreMock           func() string
+	GetUpdateVersionedMock   func(fields []string, versionField string) (string, error)
+
+	GetSelectWhereMock     func(filter Filter) (string, []any, error)
+	GetAggregateMock       func(fn AggregateFunc, column string, groupBy string, filter Filter) (string, []any, error)
+	GetCountWhereMock      func(filter Filter) (string, []any, error)
+	GetSelectForUpdateMock func(filter Filter, orderBy []OrderBy, limit int, lock rowLock) (string, []any, error)
+	GetSelectFieldsMock    func(fields []string, filter Filter) (string, []any, error)
+	GetSelectPageMock      func(filter Filter, orderBy []OrderBy, limit int, offset int) (string, []any, error)
+	GetDeleteWhereMock     func(filter Filter) (string, []any, error)
+	GetUpdateWhereMock     func(fields []string, filter Filter) (string, []any, error)
+	GetClaimMock           func(columns queueColumns, filter Filter, limit int) (string, []any, error)
 }
 
 func (s sqlTemplatesMock) GetSelect() string {
@@ -63,6 +147,18 @@ func (s sqlTemplatesMock) GetInsert(fields []string) (string, error) {
 	return s.GetInsertMock(fields)
 }
 
+func (s sqlTemplatesMock) GetInsertMany(fields []string, rows int) (string, error) {
+	return s.GetInsertManyMock(fields, rows)
+}
+
+func (s sqlTemplatesMock) GetInsertReturning(fields []string) (string, error) {
+	return s.GetInsertRetMock(fields)
+}
+
+func (s sqlTemplatesMock) GetUpsert(fields []string, conflictFields []string, updateFields []string) (string, error) {
+	return s.GetUpsertMock(fields, conflictFields, updateFields)
+}
+
 func (s sqlTemplatesMock) GetUpdate(fields []string) (string, error) {
 	return s.GetUpdateMock(fields)
 }
@@ -71,11 +167,68 @@ func (s sqlTemplatesMock) GetDelete() string {
 	return s.GetDeleteMock()
 }
 
+func (s sqlTemplatesMock) GetSelectWithDeleted() string {
+	return s.GetSelectWithDeletedMock()
+}
+
+func (s sqlTemplatesMock) GetSoftDelete() string {
+	return s.GetSoftDeleteMock()
+}
+
+func (s sqlTemplatesMock) GetRestore() string {
+	return s.GetRestoreMock()
+}
+
+func (s sqlTemplatesMock) GetUpdateVersioned(fields []string, versionField string) (string, error) {
+	return s.GetUpdateVersionedMock(fields, versionField)
+}
+
+func (s sqlTemplatesMock) GetSelectWhere(filter Filter) (string, []any, error) {
+	return s.GetSelectWhereMock(filter)
+}
+
+func (s sqlTemplatesMock) GetSelectForUpdate(filter Filter, orderBy []OrderBy, limit int, lock rowLock) (string, []any, error) {
+	return s.GetSelectForUpdateMock(filter, orderBy, limit, lock)
+}
+
+func (s sqlTemplatesMock) GetSelectFields(fields []string, filter Filter) (string, []any, error) {
+	return s.GetSelectFieldsMock(fields, filter)
+}
+
+func (s sqlTemplatesMock) GetSelectPage(filter Filter, orderBy []OrderBy, limit int, offset int) (string, []any, error) {
+	return s.GetSelectPageMock(filter, orderBy, limit, offset)
+}
+
+func (s sqlTemplatesMock) GetAggregate(fn AggregateFunc, column string, groupBy string, filter Filter) (string, []any, error) {
+	return s.GetAggregateMock(fn, column, groupBy, filter)
+}
+
+func (s sqlTemplatesMock) GetCountWhere(filter Filter) (string, []any, error) {
+	return s.GetCountWhereMock(filter)
+}
+
+func (s sqlTemplatesMock) GetDeleteWhere(filter Filter) (string, []any, error) {
+	return s.GetDeleteWhereMock(filter)
+}
+
+func (s sqlTemplatesMock) GetUpdateWhere(fields []string, filter Filter) (string, []any, error) {
+	return s.GetUpdateWhereMock(fields, filter)
+}
+
+func (s sqlTemplatesMock) GetClaim(columns queueColumns, filter Filter, limit int) (string, []any, error) {
+	return s.GetClaimMock(columns, filter, limit)
+}
+
 type structParserMock struct {
 	StructParser
 
 	ParseFieldNamesMock func(typ reflect.Type) ([]string, error)
 	ParsePropertiesMock func(model any, idFieldName string) ([]string, []any, error)
+	FieldPointerMock    func(model any, fieldName string) (any, error)
+}
+
+func (s structParserMock) FieldPointer(model any, fieldName string) (any, error) {
+	return s.FieldPointerMock(model, fieldName)
 }
 
 func (s structParserMock) ParseProperties(model any, idFieldName string) ([]string, []any, error) {
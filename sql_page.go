@@ -0,0 +1,47 @@
+package dvbcrud
+
+// OrderBy sorts the rows of a read by Column, in descending order if Descending is set.
+type OrderBy struct {
+	Column     string
+	Descending bool
+}
+
+// Asc sorts by column in ascending order.
+func Asc(column string) OrderBy {
+	return OrderBy{Column: column}
+}
+
+// Desc sorts by column in descending order.
+func Desc(column string) OrderBy {
+	return OrderBy{Column: column, Descending: true}
+}
+
+// PageRequest selects a page of rows for ReadPage.
+type PageRequest struct {
+	// Limit is the maximum number of rows in the page and must be at least 1.
+	Limit int
+	// Offset is the number of rows to skip.
+	Offset int
+	// OrderBy sorts the rows before paging. Defaults to the ID column, so that pages are stable.
+	OrderBy []OrderBy
+	// Filter restricts the rows to page through. A nil filter matches all rows.
+	Filter Filter
+	// WithTotal also counts the rows that match Filter, at the cost of an extra query.
+	WithTotal bool
+}
+
+// Page holds the rows returned by ReadPage.
+type Page[T any] struct {
+	Items  []T
+	Limit  int
+	Offset int
+	// Total is the number of rows that match the filter, or nil unless WithTotal was set.
+	Total *int64
+}
+
+// CursorPage holds the rows returned by ReadAfter.
+type CursorPage[T any] struct {
+	Items []T
+	// NextCursor is passed to ReadAfter to read the next page. It is empty on the last page.
+	NextCursor string
+}
@@ -4,64 +4,37 @@ import (
 	"fmt"
 )
 
-// parameterType separates Column and Value parameter types.
+// ParameterType separates Column and Value parameter types.
 // This is only applicable to prepared statements in Oracle.
-type parameterType int
+type ParameterType int
 
 const (
-	Columns parameterType = iota
+	Columns ParameterType = iota
 	Values
 )
 
 type sqlParameterGenerator interface {
-	GetParamPlaceholders(amount int, typ parameterType) ([]string, error)
+	GetParamPlaceholders(offset int, amount int, typ ParameterType) ([]string, error)
 }
 
 type sqlParameterGeneratorImpl struct {
 	sqlParameterGenerator
-	dialect SQLDialect
+	dialect Dialect
 }
 
 // GetParamPlaceholders returns n amount of parameter placeholders as an array of strings.
-// The placeholders are formatted according to the chosen dialect.
-// (e.g. MySQL-like = ?, PostgreSQL = $1, Oracle = :col1 or :var1)
-func (p sqlParameterGeneratorImpl) GetParamPlaceholders(amount int, typ parameterType) ([]string, error) {
-	placeholders := make([]string, amount)
-
-	switch p.dialect {
-	case MySQL, SQLite, ODBC, MariaDB:
-		for i := 0; i < amount; i++ {
-			placeholders[i] = "?"
-		}
-
-	case PostgreSQL:
-		for i := 0; i < amount; i++ {
-			placeholders[i] = fmt.Sprintf("$%d", i+1)
-		}
-
-	case Oracle:
-		var name string
-		if typ == Columns {
-			name = "col"
-		} else {
-			name = "val"
-		}
-		if amount == 1 {
-			placeholders[0] = ":" + name
-			break
-		}
-		for i := 0; i < amount; i++ {
-			placeholders[i] = fmt.Sprintf(":%s%d", name, i+1)
-		}
-
-	default:
+// The placeholders are formatted according to the chosen dialect and numbered after
+// the offset parameters that precede them.
+// (e.g. MySQL-like = ?, PostgreSQL = $1, SQL Server = @p1, Oracle = :col1 or :var1)
+func (p sqlParameterGeneratorImpl) GetParamPlaceholders(offset int, amount int, typ ParameterType) ([]string, error) {
+	if p.dialect == nil {
 		return nil, fmt.Errorf("unknown dialect")
 	}
 
-	return placeholders, nil
+	return p.dialect.Placeholders(offset, amount, typ), nil
 }
 
-func newSQLParamGen(dialect SQLDialect) sqlParameterGenerator {
+func newSQLParamGen(dialect Dialect) sqlParameterGenerator {
 	return sqlParameterGeneratorImpl{
 		dialect: dialect,
 	}
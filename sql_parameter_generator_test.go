@@ -9,7 +9,7 @@ func TestSqlParameterGeneratorImpl_GetParamPlaceholders_MySQL(t *testing.T) {
 	gen := newSQLParamGen(MySQL)
 	expected := []string{"?", "?", "?"}
 
-	actual, _ := gen.GetParamPlaceholders(3, Columns)
+	actual, _ := gen.GetParamPlaceholders(0, 3, Columns)
 
 	if !reflect.DeepEqual(actual, expected) {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -20,7 +20,7 @@ func TestSqlParameterGeneratorImpl_GetParamPlaceholders_PostgreSQL(t *testing.T)
 	gen := newSQLParamGen(PostgreSQL)
 	expected := []string{"$1", "$2", "$3"}
 
-	actual, _ := gen.GetParamPlaceholders(3, Columns)
+	actual, _ := gen.GetParamPlaceholders(0, 3, Columns)
 
 	if !reflect.DeepEqual(actual, expected) {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -31,7 +31,7 @@ func TestSqlParameterGeneratorImpl_GetParamPlaceholders_OracleCol(t *testing.T)
 	gen := newSQLParamGen(Oracle)
 	expected := []string{":col1", ":col2", ":col3"}
 
-	actual, _ := gen.GetParamPlaceholders(3, Columns)
+	actual, _ := gen.GetParamPlaceholders(0, 3, Columns)
 
 	if !reflect.DeepEqual(actual, expected) {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -42,7 +42,7 @@ func TestSqlParameterGeneratorImpl_GetParamPlaceholders_OracleVal(t *testing.T)
 	gen := newSQLParamGen(Oracle)
 	expected := []string{":val1", ":val2", ":val3"}
 
-	actual, _ := gen.GetParamPlaceholders(3, Values)
+	actual, _ := gen.GetParamPlaceholders(0, 3, Values)
 
 	if !reflect.DeepEqual(actual, expected) {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -53,7 +53,7 @@ func TestSqlParameterGeneratorImpl_GetParamPlaceholders_OracleColSingle(t *testi
 	gen := newSQLParamGen(Oracle)
 	expected := []string{":col"}
 
-	actual, _ := gen.GetParamPlaceholders(1, Columns)
+	actual, _ := gen.GetParamPlaceholders(0, 1, Columns)
 
 	if !reflect.DeepEqual(actual, expected) {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -64,7 +64,7 @@ func TestSqlParameterGeneratorImpl_GetParamPlaceholders_OracleValSingle(t *testi
 	gen := newSQLParamGen(Oracle)
 	expected := []string{":val"}
 
-	actual, _ := gen.GetParamPlaceholders(1, Values)
+	actual, _ := gen.GetParamPlaceholders(0, 1, Values)
 
 	if !reflect.DeepEqual(actual, expected) {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -72,12 +72,34 @@ func TestSqlParameterGeneratorImpl_GetParamPlaceholders_OracleValSingle(t *testi
 }
 
 func TestSqlParameterGeneratorImpl_GetParamPlaceholders_UnknownDialect(t *testing.T) {
-	gen := newSQLParamGen(-1)
+	gen := newSQLParamGen(nil)
 	expected := "unknown dialect"
 
-	_, actual := gen.GetParamPlaceholders(1, Columns)
+	_, actual := gen.GetParamPlaceholders(0, 1, Columns)
 
 	if actual.Error() != expected {
 		t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
 	}
 }
+
+func TestSqlParameterGeneratorImpl_GetParamPlaceholders_SQLServer(t *testing.T) {
+	gen := newSQLParamGen(SQLServer)
+	expected := []string{"@p1", "@p2", "@p3"}
+
+	actual, _ := gen.GetParamPlaceholders(0, 3, Columns)
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSqlParameterGeneratorImpl_GetParamPlaceholders_Offset(t *testing.T) {
+	gen := newSQLParamGen(PostgreSQL)
+	expected := []string{"$3", "$4"}
+
+	actual, _ := gen.GetParamPlaceholders(2, 2, Values)
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
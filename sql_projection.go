@@ -0,0 +1,62 @@
+package dvbcrud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Project fetches the columns in the db tags of P from the rows in the table of repo that match filter.
+// A nil filter matches all rows.
+func Project[T any, P any](repo *SQLRepository[T], filter Filter) ([]P, error) {
+	return ProjectContext[T, P](context.Background(), repo, filter)
+}
+
+// ProjectContext fetches the columns in the db tags of P from the rows in the table of repo that match filter.
+// A nil filter matches all rows.
+//
+// Only the columns of P are selected, which must be a subset of the columns of T.
+// This suits reads that only need a few columns, such as the ID and name for a dropdown.
+func ProjectContext[T any, P any](ctx context.Context, repo *SQLRepository[T], filter Filter) ([]P, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+
+	var projection P
+	fields, err := repo.structParser.ParseFieldNames(reflect.TypeOf(projection))
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if !contains(repo.fields, field) {
+			return nil, fmt.Errorf("column \"%s\" of %T is not a column of %T", field, projection, *new(T))
+		}
+	}
+
+	ctx, cancel := repo.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := repo.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args, err := templates.GetSelectFields(fields, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := repo.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var result []P
+	err = stmt.SelectContext(ctx, &result, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package dvbcrud
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+type projectionTestUser struct {
+	ID   uint64 `db:"UserId"`
+	Name string `db:"Name"`
+}
+
+type projectionTestInvalid struct {
+	ID  uint64 `db:"UserId"`
+	Age int    `db:"Age"`
+}
+
+func TestProject(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectFieldsMock: func(fields []string, filter Filter) (string, []any, error) {
+			if !reflect.DeepEqual(fields, []string{"UserId", "Name"}) {
+				t.Fatalf("Expected projected fields but got %v", fields)
+			}
+			return "AnySelectFields", []any{"A%"}, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectFields").
+		ExpectQuery().
+		WithArgs("A%").
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).
+			AddRow(1, "Ann").
+			AddRow(2, "Amy"))
+
+	actual, err := Project[repoTestUser, projectionTestUser](repo, Like("Name", "A%"))
+	if err != nil {
+		t.Fatalf("Expected Project to succeed, but got: %s", err)
+	}
+
+	expected := []projectionTestUser{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Amy"}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestProject_NotSubset(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "column \"Age\" of dvbcrud.projectionTestInvalid is not a column of dvbcrud.repoTestUser"
+
+	_, actual := Project[repoTestUser, projectionTestInvalid](repo, nil)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestProject_NilRepo(t *testing.T) {
+	expected := "repo cannot be nil"
+
+	_, actual := Project[repoTestUser, projectionTestUser](nil, nil)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
@@ -0,0 +1,488 @@
+package dvbcrud
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"time"
+)
+
+// Statuses of the jobs in a Queue, stored in the column tagged with the status option.
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobDead    = "dead"
+)
+
+// ErrJobLost is returned by Complete, Fail and DeadLetter when the job is no longer running on the
+// attempt it was claimed for, e.g. because its visibility timeout passed and another worker claimed it.
+var ErrJobLost = errors.New("job is no longer claimed by this worker")
+
+// queueColumns holds the names of the columns that a Queue keeps the state of its jobs in.
+type queueColumns struct {
+	status    string
+	attempts  string
+	visibleAt string
+}
+
+// QueueConfig holds the settings used by NewQueue when creating a Queue.
+// It is populated by the QueueOption functions passed to NewQueue.
+type QueueConfig struct {
+	visibilityTimeout time.Duration
+	maxAttempts       int
+	retryDelay        time.Duration
+}
+
+// QueueOption configures a Queue created by NewQueue.
+type QueueOption func(config *QueueConfig) error
+
+// WithVisibilityTimeout sets how long a claimed job stays hidden from other workers. A job that is
+// neither completed nor failed within the timeout is claimed again. Defaults to 30 seconds.
+func WithVisibilityTimeout(timeout time.Duration) QueueOption {
+	return func(config *QueueConfig) error {
+		if timeout <= 0 {
+			return fmt.Errorf("visibility timeout must be positive")
+		}
+		config.visibilityTimeout = timeout
+		return nil
+	}
+}
+
+// WithMaxAttempts sets how many times a job is claimed before it is dead-lettered. Defaults to 5.
+func WithMaxAttempts(maxAttempts int) QueueOption {
+	return func(config *QueueConfig) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("max attempts must be at least 1")
+		}
+		config.maxAttempts = maxAttempts
+		return nil
+	}
+}
+
+// WithRetryDelay sets how long a failed job waits before it can be claimed again. Defaults to 0.
+func WithRetryDelay(delay time.Duration) QueueOption {
+	return func(config *QueueConfig) error {
+		if delay < 0 {
+			return fmt.Errorf("retry delay cannot be negative")
+		}
+		config.retryDelay = delay
+		return nil
+	}
+}
+
+// Queue runs jobs stored as rows of T. T must tag one field of each of these columns with an option:
+//
+//	Status    string       `db:"status,status"`         // JobPending, JobRunning, JobDone or JobDead
+//	Attempts  int          `db:"attempts,attempts"`     // how many times the job was claimed
+//	VisibleAt sql.NullTime `db:"visible_at,visibleat"`  // when the job can be claimed (again)
+//
+// VisibleAt may also be a time.Time or a *time.Time. Jobs are claimed in the order of their IDs.
+type Queue[T any] struct {
+	repo              *SQLRepository[T]
+	columns           queueColumns
+	visibilityTimeout time.Duration
+	maxAttempts       int
+	retryDelay        time.Duration
+}
+
+// Enqueue inserts job as a pending job that can be claimed right away.
+func (q Queue[T]) Enqueue(job T) error {
+	return q.EnqueueContext(context.Background(), job)
+}
+
+// EnqueueContext inserts job as a pending job that can be claimed right away.
+func (q Queue[T]) EnqueueContext(ctx context.Context, job T) error {
+	if err := q.setState(&job, JobPending, 0, time.Now()); err != nil {
+		return err
+	}
+	return q.repo.CreateContext(ctx, job)
+}
+
+// Claim claims at most limit jobs and returns them. See ClaimContext.
+func (q Queue[T]) Claim(limit int) ([]T, error) {
+	return q.ClaimContext(context.Background(), limit)
+}
+
+// ClaimContext claims at most limit jobs and returns them, with the status set to JobRunning and
+// the attempts incremented. Jobs can be claimed while they are pending, or while they are running
+// but their visibility timeout has passed. Running jobs whose visibility timeout has passed on their
+// last attempt are dead-lettered first.
+//
+// Dialects that can lock rows select the jobs with FOR UPDATE SKIP LOCKED and update them in a
+// transaction, so that concurrent workers claim different jobs. If the repository already runs in a
+// *sqlx.Tx, that transaction is used. SQLite claims the jobs with a single UPDATE ... RETURNING.
+func (q Queue[T]) ClaimContext(ctx context.Context, limit int) ([]T, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("limit must be at least 1")
+	}
+
+	ctx, cancel := q.repo.withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now()
+	if q.repo.dialect.Locking() == LockingUnsupported {
+		return q.claimReturning(ctx, limit, now)
+	}
+
+	if _, ok := q.repo.db.(*sqlx.Tx); ok {
+		return q.claimLocked(ctx, q.repo, limit, now)
+	}
+
+	var jobs []T
+	err := RunInTxWithDialect(ctx, q.repo.db, q.repo.dialect, func(tx *sqlx.Tx) error {
+		var err error
+		jobs, err = q.claimLocked(ctx, q.repo.WithTx(tx), limit, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// claimable returns a filter that matches the jobs that can be claimed at now.
+func (q Queue[T]) claimable(now time.Time) Filter {
+	return And(
+		In(q.columns.status, JobPending, JobRunning),
+		Or(IsNull(q.columns.visibleAt), Le(q.columns.visibleAt, now)),
+		Lt(q.columns.attempts, q.maxAttempts),
+	)
+}
+
+// claimLocked locks and claims jobs with repo, which must run in a transaction.
+func (q Queue[T]) claimLocked(ctx context.Context, repo *SQLRepository[T], limit int, now time.Time) ([]T, error) {
+	if err := q.deadLetterExpired(ctx, repo, now); err != nil {
+		return nil, err
+	}
+
+	templates, err := repo.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := make([]OrderBy, len(repo.idFields))
+	for i, idField := range repo.idFields {
+		orderBy[i] = Asc(idField)
+	}
+
+	query, args, err := templates.GetSelectForUpdate(q.claimable(now), orderBy, limit, rowLock{skipLocked: true})
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := repo.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var jobs []T
+	if err := stmt.SelectContext(ctx, &jobs, args...); err != nil {
+		return nil, err
+	}
+
+	visibleAt := now.Add(q.visibilityTimeout)
+	for i := range jobs {
+		attempts, err := q.attempts(&jobs[i])
+		if err != nil {
+			return nil, err
+		}
+		attempts++
+
+		if err := q.setState(&jobs[i], JobRunning, attempts, visibleAt); err != nil {
+			return nil, err
+		}
+
+		key, err := repo.modelKey(&jobs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		err = repo.PatchContext(ctx, key, map[string]any{
+			q.columns.status:    JobRunning,
+			q.columns.attempts:  attempts,
+			q.columns.visibleAt: visibleAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.incrementVersion(&jobs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+// claimReturning claims jobs with a single UPDATE ... RETURNING statement.
+func (q Queue[T]) claimReturning(ctx context.Context, limit int, now time.Time) ([]T, error) {
+	if err := q.deadLetterExpired(ctx, q.repo, now); err != nil {
+		return nil, err
+	}
+
+	templates, err := q.repo.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := templates.GetClaim(q.columns, q.claimable(now), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := q.repo.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var jobs []T
+	args = append([]any{JobRunning, now.Add(q.visibilityTimeout)}, args...)
+	if err := stmt.SelectContext(ctx, &jobs, args...); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// deadLetterExpired dead-letters the running jobs whose visibility timeout has passed on their last attempt.
+func (q Queue[T]) deadLetterExpired(ctx context.Context, repo *SQLRepository[T], now time.Time) error {
+	templates, err := repo.getTemplates(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := templates.GetUpdateWhere([]string{q.columns.status}, And(
+		Eq(q.columns.status, JobRunning),
+		Le(q.columns.visibleAt, now),
+		Ge(q.columns.attempts, q.maxAttempts),
+	))
+	if err != nil {
+		return err
+	}
+
+	stmt, err := repo.db.PreparexContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, append([]any{JobDead}, args...)...)
+	return err
+}
+
+// Complete marks job as done.
+func (q Queue[T]) Complete(job T) error {
+	return q.CompleteContext(context.Background(), job)
+}
+
+// CompleteContext marks job as done.
+func (q Queue[T]) CompleteContext(ctx context.Context, job T) error {
+	return q.setStatus(ctx, job, []string{q.columns.status}, []any{JobDone})
+}
+
+// Fail releases job after a failed attempt.
+func (q Queue[T]) Fail(job T) error {
+	return q.FailContext(context.Background(), job)
+}
+
+// FailContext releases job after a failed attempt. The job is pending again once the retry
+// delay has passed, or dead-lettered if it has used up its attempts.
+func (q Queue[T]) FailContext(ctx context.Context, job T) error {
+	attempts, err := q.attempts(&job)
+	if err != nil {
+		return err
+	}
+
+	if attempts >= int64(q.maxAttempts) {
+		return q.setStatus(ctx, job, []string{q.columns.status}, []any{JobDead})
+	}
+
+	return q.setStatus(ctx, job,
+		[]string{q.columns.status, q.columns.visibleAt},
+		[]any{JobPending, time.Now().Add(q.retryDelay)})
+}
+
+// DeadLetter marks job as dead, so that it is never claimed again.
+func (q Queue[T]) DeadLetter(job T) error {
+	return q.DeadLetterContext(context.Background(), job)
+}
+
+// DeadLetterContext marks job as dead, so that it is never claimed again.
+func (q Queue[T]) DeadLetterContext(ctx context.Context, job T) error {
+	return q.setStatus(ctx, job, []string{q.columns.status}, []any{JobDead})
+}
+
+// setStatus sets fields to values in the row of job, as long as the job is still running on the
+// attempt it was claimed for. Returns ErrJobLost otherwise.
+func (q Queue[T]) setStatus(ctx context.Context, job T, fields []string, values []any) error {
+	key, err := q.repo.modelKey(&job)
+	if err != nil {
+		return err
+	}
+	keyFilter, err := q.repo.keyFilter(key)
+	if err != nil {
+		return err
+	}
+	attempts, err := q.attempts(&job)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := q.repo.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := q.repo.getTemplates(ctx, &job)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := templates.GetUpdateWhere(fields, And(
+		keyFilter,
+		Eq(q.columns.status, JobRunning),
+		Eq(q.columns.attempts, attempts),
+	))
+	if err != nil {
+		return err
+	}
+
+	stmt, err := q.repo.db.PreparexContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	exec, err := stmt.ExecContext(ctx, append(values, args...)...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := exec.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrJobLost
+	}
+
+	return nil
+}
+
+// attempts returns the attempts of job.
+func (q Queue[T]) attempts(job *T) (int64, error) {
+	ptr, err := q.repo.structParser.FieldPointer(job, q.columns.attempts)
+	if err != nil {
+		return 0, err
+	}
+
+	attempts, err := readInt64(ptr)
+	if err != nil {
+		return 0, fmt.Errorf("field tagged \"%s\": %w", q.columns.attempts, err)
+	}
+	return attempts, nil
+}
+
+// setState sets the status, attempts and visible at fields of job.
+func (q Queue[T]) setState(job *T, status string, attempts int64, visibleAt time.Time) error {
+	values := map[string]any{
+		q.columns.status:    status,
+		q.columns.attempts:  attempts,
+		q.columns.visibleAt: visibleAt,
+	}
+
+	for column, value := range values {
+		ptr, err := q.repo.structParser.FieldPointer(job, column)
+		if err != nil {
+			return err
+		}
+		if err := assignValue(ptr, value); err != nil {
+			return fmt.Errorf("field tagged \"%s\": %w", column, err)
+		}
+	}
+
+	return nil
+}
+
+// assignValue sets the value dest points to to value. dest may be an sql.Scanner, a pointer
+// to a pointer, or a pointer to a type that value converts to.
+func assignValue(dest any, value any) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	target := reflect.ValueOf(dest).Elem()
+	source := reflect.ValueOf(value)
+	if target.Kind() == reflect.Pointer {
+		if source.Type() != target.Type().Elem() {
+			return fmt.Errorf("cannot assign %T to a field of type %s", value, target.Type())
+		}
+		ptr := reflect.New(source.Type())
+		ptr.Elem().Set(source)
+		target.Set(ptr)
+		return nil
+	}
+
+	// Numbers convert to strings as runes, so kinds must match as well
+	if !source.CanConvert(target.Type()) || (source.Kind() == reflect.String) != (target.Kind() == reflect.String) {
+		return fmt.Errorf("cannot assign %T to a field of type %s", value, target.Type())
+	}
+
+	target.Set(source.Convert(target.Type()))
+	return nil
+}
+
+// NewQueue creates and returns a new Queue that stores its jobs with repo.
+// The status, attempts and visible at columns are found through the tag options of T.
+func NewQueue[T any](repo *SQLRepository[T], options ...QueueOption) (*Queue[T], error) {
+	if repo == nil {
+		return nil, fmt.Errorf("repo cannot be nil")
+	}
+
+	config := QueueConfig{
+		visibilityTimeout: 30 * time.Second,
+		maxAttempts:       5,
+	}
+	for _, option := range options {
+		if err := option(&config); err != nil {
+			return nil, err
+		}
+	}
+
+	var hack T
+	typ := reflect.TypeOf(hack)
+
+	var columns [3]string
+	for i, option := range []string{"status", "attempts", "visibleat"} {
+		fields, err := repo.structParser.ParseTaggedFields(typ, option)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("%s must have exactly one field tagged \"%s\"", typ.Name(), option)
+		}
+		columns[i] = fields[0]
+	}
+
+	queue := &Queue[T]{
+		repo:              repo,
+		columns:           queueColumns{status: columns[0], attempts: columns[1], visibleAt: columns[2]},
+		visibilityTimeout: config.visibilityTimeout,
+		maxAttempts:       config.maxAttempts,
+		retryDelay:        config.retryDelay,
+	}
+
+	// Catch fields of the wrong types here rather than when the first job is claimed
+	if err := queue.setState(&hack, JobPending, 0, time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := queue.attempts(&hack); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
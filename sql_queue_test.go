@@ -0,0 +1,308 @@
+package dvbcrud
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"testing"
+	"time"
+)
+
+type queueTestJob struct {
+	ID        int64        `db:"id"`
+	Payload   string       `db:"payload"`
+	Status    string       `db:"status,status"`
+	Attempts  int          `db:"attempts,attempts"`
+	VisibleAt sql.NullTime `db:"visible_at,visibleat"`
+}
+
+type queueTestMissingStatus struct {
+	ID        int64     `db:"id"`
+	Attempts  int       `db:"attempts,attempts"`
+	VisibleAt time.Time `db:"visible_at,visibleat"`
+}
+
+type queueTestWrongType struct {
+	ID        int64     `db:"id"`
+	Status    int       `db:"status,status"`
+	Attempts  int       `db:"attempts,attempts"`
+	VisibleAt time.Time `db:"visible_at,visibleat"`
+}
+
+type queueTestVersionedJob struct {
+	ID        int64        `db:"id"`
+	Status    string       `db:"status,status"`
+	Attempts  int          `db:"attempts,attempts"`
+	VisibleAt sql.NullTime `db:"visible_at,visibleat"`
+	Version   int          `db:"version,version"`
+}
+
+var queueTestColumns = []string{"id", "payload", "status", "attempts", "visible_at"}
+
+func TestNewQueue(t *testing.T) {
+	repo, _, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo, WithMaxAttempts(3))
+	defer closeDB()
+	expected := queueColumns{status: "status", attempts: "attempts", visibleAt: "visible_at"}
+
+	if queue.columns != expected || queue.maxAttempts != 3 || queue.visibilityTimeout != 30*time.Second {
+		t.Fatalf("Expected columns %v and 3 attempts but got %v", expected, queue)
+	}
+}
+
+func TestNewQueue_MissingColumn(t *testing.T) {
+	repo, mockDB, _, _ := newMock[queueTestMissingStatus]()
+	defer mockDB.Close()
+	expected := "queueTestMissingStatus must have exactly one field tagged \"status\""
+
+	_, actual := NewQueue(repo)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestNewQueue_WrongType(t *testing.T) {
+	repo, mockDB, _, _ := newMock[queueTestWrongType]()
+	defer mockDB.Close()
+	expected := "field tagged \"status\": cannot assign string to a field of type int"
+
+	_, actual := NewQueue(repo)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestNewQueue_InvalidOption(t *testing.T) {
+	repo, mockDB, _, _ := newMock[queueTestJob]()
+	defer mockDB.Close()
+	expected := "max attempts must be at least 1"
+
+	_, actual := NewQueue(repo, WithMaxAttempts(0))
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestQueue_Enqueue(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo)
+	defer closeDB()
+
+	mock.ExpectPrepare("INSERT INTO jobs (payload, status, attempts, visible_at) VALUES ($1, $2, $3, $4)").
+		ExpectExec().
+		WithArgs("AnyPayload", JobPending, 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := queue.Enqueue(queueTestJob{Payload: "AnyPayload", Status: JobDone, Attempts: 2})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestQueue_Claim_SkipLocked(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo, WithMaxAttempts(3))
+	defer closeDB()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE jobs SET status = $1 WHERE (status = $2) AND (visible_at <= $3) AND (attempts >= $4)").
+		ExpectExec().
+		WithArgs(JobDead, JobRunning, sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("SELECT id, payload, status, attempts, visible_at FROM jobs "+
+		"WHERE (status IN ($1, $2)) AND ((visible_at IS NULL) OR (visible_at <= $3)) AND (attempts < $4) "+
+		"ORDER BY id LIMIT 2 FOR UPDATE SKIP LOCKED").
+		ExpectQuery().
+		WithArgs(JobPending, JobRunning, sqlmock.AnyArg(), 3).
+		WillReturnRows(sqlmock.NewRows(queueTestColumns).
+			AddRow(1, "AnyPayload", JobPending, 0, nil).
+			AddRow(2, "AnyPayload", JobRunning, 1, time.Now()))
+	for id, attempts := range []int64{1, 2} {
+		mock.ExpectPrepare("UPDATE jobs SET attempts = $1, status = $2, visible_at = $3 WHERE id = $4").
+			ExpectExec().
+			WithArgs(attempts, JobRunning, sqlmock.AnyArg(), id+1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	actual, err := queue.Claim(2)
+
+	if err != nil || len(actual) != 2 {
+		t.Fatalf("Expected 2 jobs but got %v (%v)", actual, err)
+	}
+	if actual[1].Status != JobRunning || actual[1].Attempts != 2 || !actual[1].VisibleAt.Time.After(time.Now()) {
+		t.Fatalf("Expected the job to be running on its second attempt but got %v", actual[1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestQueue_Claim_Version(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestVersionedJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo)
+	defer closeDB()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE jobs SET status = $1, version = version + 1 "+
+		"WHERE (status = $2) AND (visible_at <= $3) AND (attempts >= $4)").
+		ExpectExec().
+		WithArgs(JobDead, JobRunning, sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectPrepare("SELECT id, status, attempts, visible_at, version FROM jobs "+
+		"WHERE (status IN ($1, $2)) AND ((visible_at IS NULL) OR (visible_at <= $3)) AND (attempts < $4) "+
+		"ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED").
+		ExpectQuery().
+		WithArgs(JobPending, JobRunning, sqlmock.AnyArg(), 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "attempts", "visible_at", "version"}).
+			AddRow(1, JobPending, 0, nil, 7))
+	mock.ExpectPrepare("UPDATE jobs SET attempts = $1, status = $2, visible_at = $3, version = version + 1 WHERE id = $4").
+		ExpectExec().
+		WithArgs(int64(1), JobRunning, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	actual, err := queue.Claim(1)
+
+	if err != nil || len(actual) != 1 || actual[0].Version != 8 {
+		t.Fatalf("Expected 1 job on version 8 but got %v (%v)", actual, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestQueue_Claim_Returning(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](SQLite, WithTable("jobs"))
+	queue, _ := NewQueue(repo)
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE jobs SET status = ? WHERE (status = ?) AND (visible_at <= ?) AND (attempts >= ?)").
+		ExpectExec().
+		WithArgs(JobDead, JobRunning, sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectPrepare("UPDATE jobs SET status = ?, visible_at = ?, attempts = attempts + 1 WHERE id IN ("+
+		"SELECT id FROM jobs WHERE (status IN (?, ?)) AND ((visible_at IS NULL) OR (visible_at <= ?)) AND (attempts < ?) "+
+		"ORDER BY id LIMIT 10) RETURNING id, payload, status, attempts, visible_at").
+		ExpectQuery().
+		WithArgs(JobRunning, sqlmock.AnyArg(), JobPending, JobRunning, sqlmock.AnyArg(), 5).
+		WillReturnRows(sqlmock.NewRows(queueTestColumns).
+			AddRow(1, "AnyPayload", JobRunning, 1, time.Now()))
+
+	actual, err := queue.Claim(10)
+
+	if err != nil || len(actual) != 1 || actual[0].Attempts != 1 {
+		t.Fatalf("Expected 1 job on its first attempt but got %v (%v)", actual, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestQueue_Claim_InvalidLimit(t *testing.T) {
+	repo, _, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo)
+	defer closeDB()
+	expected := "limit must be at least 1"
+
+	_, actual := queue.Claim(0)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestQueue_Complete(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo)
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE jobs SET status = $1 WHERE (id = $2) AND (status = $3) AND (attempts = $4)").
+		ExpectExec().
+		WithArgs(JobDone, 1, JobRunning, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := queue.Complete(queueTestJob{ID: 1, Status: JobRunning, Attempts: 1})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestQueue_Fail_Retry(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo, WithMaxAttempts(3), WithRetryDelay(time.Minute))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE jobs SET status = $1, visible_at = $2 WHERE (id = $3) AND (status = $4) AND (attempts = $5)").
+		ExpectExec().
+		WithArgs(JobPending, sqlmock.AnyArg(), 1, JobRunning, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := queue.Fail(queueTestJob{ID: 1, Status: JobRunning, Attempts: 2})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestQueue_Fail_LastAttempt(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo, WithMaxAttempts(3))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE jobs SET status = $1 WHERE (id = $2) AND (status = $3) AND (attempts = $4)").
+		ExpectExec().
+		WithArgs(JobDead, 1, JobRunning, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := queue.Fail(queueTestJob{ID: 1, Status: JobRunning, Attempts: 3})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestQueue_DeadLetter(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo)
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE jobs SET status = $1 WHERE (id = $2) AND (status = $3) AND (attempts = $4)").
+		ExpectExec().
+		WithArgs(JobDead, 1, JobRunning, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := queue.DeadLetter(queueTestJob{ID: 1, Status: JobRunning, Attempts: 1})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestQueue_Fail_Lost(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[queueTestJob](PostgreSQL, WithTable("jobs"))
+	queue, _ := NewQueue(repo, WithMaxAttempts(3))
+	defer closeDB()
+
+	// The visibility timeout passed and another worker claimed the job for its second attempt
+	mock.ExpectPrepare("UPDATE jobs SET status = $1, visible_at = $2 WHERE (id = $3) AND (status = $4) AND (attempts = $5)").
+		ExpectExec().
+		WithArgs(JobPending, sqlmock.AnyArg(), 1, JobRunning, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := queue.Fail(queueTestJob{ID: 1, Status: JobRunning, Attempts: 1})
+
+	if err != ErrJobLost {
+		t.Fatalf("Expected ErrJobLost but got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
@@ -1,46 +1,118 @@
 package dvbcrud
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"math"
 	"reflect"
+	"sort"
+	"time"
 )
 
 // SQLRepository handles CRUD queries to a table in an SQL database.
 // T is the struct type that will be mapped against the table rows.
 type SQLRepository[T any] struct {
-	db           *sqlx.DB
-	templates    sqlTemplates
-	structParser StructParser
-	idField      string
+	db             Executor
+	templates      sqlTemplates
+	structParser   StructParser
+	idFields       []string
+	fields         []string
+	softDelete     string
+	version        string
+	dialect        Dialect
+	tableResolver  TableResolver
+	templatesCache *sqlTemplatesCache
+	timeout        time.Duration
+	cursor         cursorCodec
 }
 
-type SQLRepositoryConfig struct {
-	dialect SQLDialect
-	table   string
-	idField string
-	fields  []string
+// WithTx returns a copy of the repository that runs its statements in tx.
+func (r SQLRepository[T]) WithTx(tx *sqlx.Tx) *SQLRepository[T] {
+	return r.WithExecutor(tx)
+}
+
+// WithExecutor returns a copy of the repository that runs its statements with executor,
+// e.g. a *sqlx.Tx or a *sqlx.Conn.
+func (r SQLRepository[T]) WithExecutor(executor Executor) *SQLRepository[T] {
+	r.db = executor
+	return &r
+}
+
+// withTimeout applies the default timeout to ctx, unless ctx already has a deadline.
+func (r SQLRepository[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+// getTemplates returns the templates for the table that the call should query.
+// Without a TableResolver, these are the templates of the table given to New.
+func (r SQLRepository[T]) getTemplates(ctx context.Context, model any) (sqlTemplates, error) {
+	table, err := r.resolveTable(ctx, model)
+	if err != nil || table == "" {
+		return r.templates, err
+	}
+
+	return r.templatesCache.Get(table)
+}
+
+// resolveTable returns the table that the call should query,
+// or an empty string if the repository has no TableResolver.
+func (r SQLRepository[T]) resolveTable(ctx context.Context, model any) (string, error) {
+	if r.tableResolver == nil {
+		return "", nil
+	}
+
+	table, err := r.tableResolver(ctx, model)
+	if err != nil {
+		return "", err
+	}
+	if err := validateTableName(table); err != nil {
+		return "", err
+	}
+
+	return table, nil
 }
 
 // Create inserts the values in model into a new row in the table.
 func (r SQLRepository[T]) Create(model T) error {
-	fields, values, err := r.structParser.ParseProperties(model, r.idField)
+	return r.CreateContext(context.Background(), model)
+}
+
+// CreateContext inserts the values in model into a new row in the table.
+func (r SQLRepository[T]) CreateContext(ctx context.Context, model T) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	fields, values, err := r.insertProperties(model)
+	if err != nil {
+		return err
+	}
+
+	templates, err := r.getTemplates(ctx, model)
 	if err != nil {
 		return err
 	}
 
-	sql, err := r.templates.GetInsert(fields)
+	sql, err := templates.GetInsert(fields)
 	if err != nil {
 		return err
 	}
 
-	stmt, err := r.db.Preparex(sql)
+	stmt, err := r.db.PreparexContext(ctx, sql)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	exec, err := stmt.Exec(values...)
+	exec, err := stmt.ExecContext(ctx, values...)
 	if err != nil {
 		return err
 	}
@@ -56,17 +128,386 @@ func (r SQLRepository[T]) Create(model T) error {
 	return nil
 }
 
+// CreateReturning inserts the values in model into a new row in the table,
+// and returns the model with the values generated by the database, such as its ID.
+func (r SQLRepository[T]) CreateReturning(model T) (*T, error) {
+	return r.CreateReturningContext(context.Background(), model)
+}
+
+// CreateReturningContext inserts the values in model into a new row in the table,
+// and returns the model with the values generated by the database, such as its ID.
+//
+// Dialects with RETURNING or OUTPUT INSERTED return the whole inserted row. Dialects that
+// use LastInsertId or RETURNING INTO return model with only its ID populated, or model as-is
+// with a composite key, which is never generated.
+func (r SQLRepository[T]) CreateReturningContext(ctx context.Context, model T) (*T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if len(r.idFields) > 1 {
+		style := r.dialect.Returning()
+		if style == ReturningLastInsertID || style == ReturningInto {
+			if err := r.CreateContext(ctx, model); err != nil {
+				return nil, err
+			}
+			return &model, nil
+		}
+	}
+
+	fields, values, err := r.insertProperties(model)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := r.getTemplates(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := templates.GetInsertReturning(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	result := model
+	switch r.dialect.Returning() {
+	case ReturningClause, ReturningOutput:
+		err = stmt.QueryRowxContext(ctx, values...).StructScan(&result)
+		if err != nil {
+			return nil, err
+		}
+
+	case ReturningLastInsertID:
+		exec, err := stmt.ExecContext(ctx, values...)
+		if err != nil {
+			return nil, err
+		}
+
+		affected, err := exec.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected != 1 {
+			return nil, fmt.Errorf("%d rows affected by INSERT INTO statement", affected)
+		}
+
+		id, err := exec.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+
+		dest, err := r.structParser.FieldPointer(&result, r.idFields[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := assignInt64(dest, id); err != nil {
+			return nil, err
+		}
+
+	case ReturningInto:
+		dest, err := r.structParser.FieldPointer(&result, r.idFields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = stmt.ExecContext(ctx, append(values, sql.Out{Dest: dest})...)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("dialect %s cannot return generated values", r.dialect.Name())
+	}
+
+	return &result, nil
+}
+
+// CreateMany inserts the values in models into new rows in the table,
+// and returns the number of rows affected.
+func (r SQLRepository[T]) CreateMany(models []T) (int64, error) {
+	return r.CreateManyContext(context.Background(), models)
+}
+
+// CreateManyContext inserts the values in models into new rows in the table,
+// and returns the number of rows affected.
+//
+// The rows are inserted with multi-row INSERT statements, split into batches that stay
+// within the parameter and row limits of the dialect. The batches are not atomic unless
+// the repository runs in a transaction (see WithTx).
+func (r SQLRepository[T]) CreateManyContext(ctx context.Context, models []T) (int64, error) {
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// A TableResolver may send each model to a different table, so the rows are
+	// grouped by table, in the order that the tables first appear in models.
+	var fields []string
+	var tables []string
+	values := make(map[string][][]any)
+	for _, model := range models {
+		modelFields, modelValues, err := r.insertProperties(model)
+		if err != nil {
+			return 0, err
+		}
+		table, err := r.resolveTable(ctx, model)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, ok := values[table]; !ok {
+			tables = append(tables, table)
+		}
+		fields = modelFields
+		values[table] = append(values[table], modelValues)
+	}
+
+	batchSize := r.batchSize(len(fields))
+	var total int64
+	for _, table := range tables {
+		templates := r.templates
+		if table != "" {
+			var err error
+			templates, err = r.templatesCache.Get(table)
+			if err != nil {
+				return total, err
+			}
+		}
+
+		rows := values[table]
+		for start := 0; start < len(rows); start += batchSize {
+			end := start + batchSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+
+			affected, err := r.insertBatch(ctx, templates, fields, rows[start:end])
+			if err != nil {
+				return total, err
+			}
+			total += affected
+		}
+	}
+
+	return total, nil
+}
+
+// insertBatch inserts rows with a single multi-row INSERT statement.
+func (r SQLRepository[T]) insertBatch(ctx context.Context, templates sqlTemplates, fields []string, rows [][]any) (int64, error) {
+	sql, err := templates.GetInsertMany(fields, len(rows))
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	args := make([]any, 0, len(rows)*len(fields))
+	for _, row := range rows {
+		args = append(args, row...)
+	}
+
+	exec, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return exec.RowsAffected()
+}
+
+// batchSize returns how many rows of numFields fields fit in one INSERT statement.
+func (r SQLRepository[T]) batchSize(numFields int) int {
+	if r.dialect.MultiRowInsert() == MultiRowInsertUnsupported {
+		return 1
+	}
+
+	limits := r.dialect.BatchLimits()
+	size := math.MaxInt
+	if limits.MaxParameters > 0 && numFields > 0 {
+		size = limits.MaxParameters / numFields
+	}
+	if limits.MaxRows > 0 && limits.MaxRows < size {
+		size = limits.MaxRows
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	return size
+}
+
+// assignInt64 sets the value dest points to to value, converting it to the type of dest.
+func assignInt64(dest any, value int64) error {
+	target := reflect.ValueOf(dest).Elem()
+	source := reflect.ValueOf(value)
+	if !source.CanConvert(target.Type()) {
+		return fmt.Errorf("cannot assign generated ID to a field of type %s", target.Type())
+	}
+
+	target.Set(source.Convert(target.Type()))
+	return nil
+}
+
+// readInt64 returns the integer that src points to as an int64.
+func readInt64(src any) (int64, error) {
+	val := reflect.ValueOf(src).Elem()
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(val.Uint()), nil
+	default:
+		return 0, fmt.Errorf("expected an integer but got %s", val.Type())
+	}
+}
+
+// Upsert inserts model into a new row, or updates the existing row that has the same
+// values in conflictColumns. conflictColumns defaults to the ID columns.
+func (r SQLRepository[T]) Upsert(model T, conflictColumns ...string) error {
+	return r.UpsertColumnsContext(context.Background(), model, conflictColumns, nil)
+}
+
+// UpsertContext inserts model into a new row, or updates the existing row that has the same
+// values in conflictColumns. conflictColumns defaults to the ID columns.
+func (r SQLRepository[T]) UpsertContext(ctx context.Context, model T, conflictColumns ...string) error {
+	return r.UpsertColumnsContext(ctx, model, conflictColumns, nil)
+}
+
+// UpsertColumns inserts model into a new row, or overwrites updateColumns of the existing row
+// that has the same values in conflictColumns.
+func (r SQLRepository[T]) UpsertColumns(model T, conflictColumns []string, updateColumns []string) error {
+	return r.UpsertColumnsContext(context.Background(), model, conflictColumns, updateColumns)
+}
+
+// UpsertColumnsContext inserts model into a new row, or overwrites updateColumns of the existing
+// row that has the same values in conflictColumns.
+//
+// conflictColumns defaults to the ID columns, and updateColumns defaults to every inserted
+// column except conflictColumns and the version column, which an upsert never overwrites. A single ID column is only inserted when it is a conflict column.
+// MySQL and MariaDB ignore conflictColumns and detect conflicts on any unique key.
+func (r SQLRepository[T]) UpsertColumnsContext(ctx context.Context, model T, conflictColumns []string, updateColumns []string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if len(conflictColumns) == 0 {
+		conflictColumns = r.idFields
+	}
+
+	var fields []string
+	var values []any
+	var err error
+	if contains(conflictColumns, r.idFields[0]) {
+		fields, values, err = r.structParser.ParseProperties(model, "")
+	} else {
+		fields, values, err = r.insertProperties(model)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, column := range conflictColumns {
+		if !contains(fields, column) {
+			return fmt.Errorf("unknown conflict column \"%s\"", column)
+		}
+	}
+
+	if updateColumns == nil {
+		for _, field := range fields {
+			if !contains(conflictColumns, field) && field != r.version {
+				updateColumns = append(updateColumns, field)
+			}
+		}
+	} else {
+		for _, column := range updateColumns {
+			if column == r.version {
+				return fmt.Errorf("cannot update version column \"%s\"", column)
+			}
+			if !contains(fields, column) || contains(conflictColumns, column) {
+				return fmt.Errorf("cannot update column \"%s\" in upsert", column)
+			}
+		}
+	}
+
+	templates, err := r.getTemplates(ctx, model)
+	if err != nil {
+		return err
+	}
+
+	sql, err := templates.GetUpsert(fields, conflictColumns, updateColumns)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, values...)
+	return err
+}
+
+// contains reports whether value is in values.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // Read fetches a row from the table whose ID matches id.
 func (r SQLRepository[T]) Read(id any) (*T, error) {
-	sql := r.templates.GetSelect()
-	stmt, err := r.db.Preparex(sql)
+	return r.ReadContext(context.Background(), id)
+}
+
+// ReadContext fetches a row from the table whose ID matches id.
+// Soft-deleted rows are left out, see ReadWithDeletedContext.
+func (r SQLRepository[T]) ReadContext(ctx context.Context, id any) (*T, error) {
+	return r.read(ctx, id, false)
+}
+
+// read fetches the row whose ID matches id, including soft-deleted rows if withDeleted is set.
+func (r SQLRepository[T]) read(ctx context.Context, id any, withDeleted bool) (*T, error) {
+	keyValues, err := r.keyValues(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := templates.GetSelect()
+	if withDeleted {
+		sql = templates.GetSelectWithDeleted()
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
 	var result T
-	err = stmt.QueryRowx(id).StructScan(&result)
+	err = stmt.QueryRowxContext(ctx, keyValues...).StructScan(&result)
 	if err != nil {
 		return nil, err
 	}
@@ -76,42 +517,493 @@ func (r SQLRepository[T]) Read(id any) (*T, error) {
 
 // ReadAll fetches all rows from the table.
 func (r SQLRepository[T]) ReadAll() ([]T, error) {
-	sql := r.templates.GetSelectAll()
-	stmt, err := r.db.Preparex(sql)
+	return r.ReadAllContext(context.Background())
+}
+
+// ReadAllContext fetches all rows from the table, except for soft-deleted rows.
+func (r SQLRepository[T]) ReadAllContext(ctx context.Context) ([]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := templates.GetSelectAll()
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var result []T
+	err = stmt.SelectContext(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReadWhere fetches all rows from the table that match filter.
+// A nil filter matches all rows.
+func (r SQLRepository[T]) ReadWhere(filter Filter) ([]T, error) {
+	return r.ReadWhereContext(context.Background(), filter)
+}
+
+// ReadWhereContext fetches all rows from the table that match filter.
+// A nil filter matches all rows.
+func (r SQLRepository[T]) ReadWhereContext(ctx context.Context, filter Filter) ([]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args, err := templates.GetSelectWhere(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
 	var result []T
-	err = stmt.Select(&result)
+	err = stmt.SelectContext(ctx, &result, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ErrStop can be returned from the callback passed to Each to stop iterating without an error.
+var ErrStop = errors.New("stop iteration")
+
+// Each calls fn with each row in the table, one at a time.
+func (r SQLRepository[T]) Each(fn func(model T) error) error {
+	return r.EachWhereContext(context.Background(), nil, fn)
+}
+
+// EachContext calls fn with each row in the table, one at a time.
+func (r SQLRepository[T]) EachContext(ctx context.Context, fn func(model T) error) error {
+	return r.EachWhereContext(ctx, nil, fn)
+}
+
+// EachWhere calls fn with each row in the table that matches filter, one at a time.
+func (r SQLRepository[T]) EachWhere(filter Filter, fn func(model T) error) error {
+	return r.EachWhereContext(context.Background(), filter, fn)
+}
+
+// EachWhereContext calls fn with each row in the table that matches filter, one at a time.
+// A nil filter matches all rows.
+//
+// Unlike ReadWhere, rows are scanned as they are read instead of being collected in memory.
+// Iteration stops at the first error returned by fn, which is returned, unless it is ErrStop.
+// The default timeout applies to the whole iteration.
+func (r SQLRepository[T]) EachWhereContext(ctx context.Context, filter Filter, fn func(model T) error) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	sql, args, err := templates.GetSelectWhere(filter)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryxContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model T
+		if err := rows.StructScan(&model); err != nil {
+			return err
+		}
+
+		if err := fn(model); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ReadPage fetches a page of rows from the table, as selected by request.
+func (r SQLRepository[T]) ReadPage(request PageRequest) (*Page[T], error) {
+	return r.ReadPageContext(context.Background(), request)
+}
+
+// ReadPageContext fetches a page of rows from the table, as selected by request.
+//
+// The page is fetched with LIMIT/OFFSET or OFFSET ... FETCH, depending on the dialect, or by filtering
+// on ROWNUM on Oracle before 12c. The total number of matching rows is counted when request.WithTotal is set.
+func (r SQLRepository[T]) ReadPageContext(ctx context.Context, request PageRequest) (*Page[T], error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	sql, args, err := templates.GetSelectPage(request.Filter, request.OrderBy, request.Limit, request.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	page := Page[T]{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+	err = stmt.SelectContext(ctx, &page.Items, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.WithTotal {
+		total, err := r.CountWhereContext(ctx, request.Filter)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return &page, nil
+}
+
+// ReadAfter fetches up to limit rows that come after cursor when sorted by orderBy.
+// An empty cursor starts at the first row.
+func (r SQLRepository[T]) ReadAfter(cursor string, limit int, orderBy ...OrderBy) (*CursorPage[T], error) {
+	return r.ReadAfterContext(context.Background(), cursor, limit, orderBy...)
+}
+
+// ReadAfterContext fetches up to limit rows that come after cursor when sorted by orderBy.
+// An empty cursor starts at the first row.
+//
+// Unlike ReadPage, rows are sought by their sort keys instead of skipped, which stays fast on
+// large tables and neither skips nor repeats rows when rows are inserted or deleted in between.
+// The ID columns are appended to orderBy unless they are already in it, so that the order is unique.
+// The sort columns should not hold NULLs. The returned NextCursor must be passed with the same
+// orderBy, and cursors that have been altered are rejected with ErrInvalidCursor.
+func (r SQLRepository[T]) ReadAfterContext(ctx context.Context, cursor string, limit int, orderBy ...OrderBy) (*CursorPage[T], error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("limit must be at least 1")
+	}
+
+	orderBy, err := r.keysetOrder(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var seek Filter
+	if cursor != "" {
+		var zero T
+		types := make([]reflect.Type, len(orderBy))
+		for i, order := range orderBy {
+			ptr, err := r.structParser.FieldPointer(&zero, order.Column)
+			if err != nil {
+				return nil, err
+			}
+			types[i] = reflect.TypeOf(ptr).Elem()
+		}
+
+		values, err := r.cursor.Decode(cursor, orderBy, types)
+		if err != nil {
+			return nil, err
+		}
+		seek = seekFilter(orderBy, values, r.dialect.RowValueComparison())
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row tells whether there is a next page
+	sql, args, err := templates.GetSelectPage(seek, orderBy, limit+1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var page CursorPage[T]
+	err = stmt.SelectContext(ctx, &page.Items, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > limit {
+		page.Items = page.Items[:limit]
+
+		last := &page.Items[limit-1]
+		values := make([]any, len(orderBy))
+		for i, order := range orderBy {
+			ptr, err := r.structParser.FieldPointer(last, order.Column)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = reflect.ValueOf(ptr).Elem().Interface()
+		}
+
+		page.NextCursor, err = r.cursor.Encode(orderBy, values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &page, nil
+}
+
+// keysetOrder validates orderBy and returns it with the ID columns appended, unless they are
+// already in it. orderBy defaults to the ID columns.
+func (r SQLRepository[T]) keysetOrder(orderBy []OrderBy) ([]OrderBy, error) {
+	result := make([]OrderBy, 0, len(orderBy)+len(r.idFields))
+	var columns []string
+	for _, order := range orderBy {
+		if !contains(r.fields, order.Column) {
+			return nil, fmt.Errorf("unknown column \"%s\" in order by", order.Column)
+		}
+		columns = append(columns, order.Column)
+		result = append(result, order)
+	}
+
+	for _, idField := range r.idFields {
+		if !contains(columns, idField) {
+			result = append(result, Asc(idField))
+		}
+	}
 	return result, nil
 }
 
+// CountWhere returns the number of rows in the table that match filter.
+// A nil filter counts all rows.
+func (r SQLRepository[T]) CountWhere(filter Filter) (int64, error) {
+	return r.CountWhereContext(context.Background(), filter)
+}
+
+// CountWhereContext returns the number of rows in the table that match filter.
+// A nil filter counts all rows.
+func (r SQLRepository[T]) CountWhereContext(ctx context.Context, filter Filter) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	sql, args, err := templates.GetCountWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var count int64
+	err = stmt.QueryRowxContext(ctx, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 // Update updates the row in the table, whose ID matches id, with the data found in model.
-func (r SQLRepository[T]) Update(id any, model T) error {
-	fields, values, err := r.structParser.ParseProperties(model, r.idField)
+func (r SQLRepository[T]) Update(id any, model *T) error {
+	return r.UpdateContext(context.Background(), id, model)
+}
+
+// UpdateContext updates the row in the table, whose ID matches id, with the data found in model.
+//
+// If the model has a field tagged version, the row is only updated if its version still matches
+// the one in model, and ErrStaleObject is returned otherwise. The version is incremented, and
+// model receives the new version.
+func (r SQLRepository[T]) UpdateContext(ctx context.Context, id any, model *T) error {
+	if model == nil {
+		return fmt.Errorf("model cannot be nil")
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	fields, values, err := r.nonKeyProperties(*model)
+	if err != nil {
+		return err
+	}
+
+	return r.updateModel(ctx, id, model, fields, values)
+}
+
+// UpdateColumns updates columns of the row in the table, whose ID matches id,
+// with the data found in model. Other columns are left untouched.
+func (r SQLRepository[T]) UpdateColumns(id any, model *T, columns ...string) error {
+	return r.UpdateColumnsContext(context.Background(), id, model, columns...)
+}
+
+// UpdateColumnsContext updates columns of the row in the table, whose ID matches id,
+// with the data found in model. Other columns are left untouched.
+// Like UpdateContext, the version column is checked and incremented if the model has one.
+func (r SQLRepository[T]) UpdateColumnsContext(ctx context.Context, id any, model *T, columns ...string) error {
+	if model == nil {
+		return fmt.Errorf("model cannot be nil")
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if err := r.validateUpdateColumns(columns); err != nil {
+		return err
+	}
+
+	fields, values, err := r.nonKeyProperties(*model)
+	if err != nil {
+		return err
+	}
+
+	columnValues := make([]any, len(columns))
+	for i, column := range columns {
+		for j, field := range fields {
+			if field == column {
+				columnValues[i] = values[j]
+			}
+		}
+	}
+
+	return r.updateModel(ctx, id, model, columns, columnValues)
+}
+
+// Patch updates the row in the table, whose ID matches id, with the values in fields,
+// which maps column names to values. Other columns are left untouched.
+func (r SQLRepository[T]) Patch(id any, fields map[string]any) error {
+	return r.PatchContext(context.Background(), id, fields)
+}
+
+// PatchContext updates the row in the table, whose ID matches id, with the values in fields,
+// which maps column names to values. Other columns are left untouched.
+// Unlike UpdateContext, the version column is neither checked nor incremented.
+func (r SQLRepository[T]) PatchContext(ctx context.Context, id any, fields map[string]any) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	if err := r.validateUpdateColumns(columns); err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		values[i] = fields[column]
+	}
+
+	return r.update(ctx, id, nil, columns, values, nil)
+}
+
+// validateUpdateColumns returns an error unless columns is a non-empty set of
+// the model's non-ID columns, other than the version and soft delete columns.
+func (r SQLRepository[T]) validateUpdateColumns(columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns to update")
+	}
+
+	for i, column := range columns {
+		if contains(r.idFields, column) {
+			return fmt.Errorf("cannot update ID column \"%s\"", column)
+		}
+		if column == r.version {
+			return fmt.Errorf("cannot update version column \"%s\"", column)
+		}
+		if column == r.softDelete {
+			return fmt.Errorf("cannot update soft delete column \"%s\", use Delete or Restore", column)
+		}
+		if !contains(r.fields, column) {
+			return fmt.Errorf("unknown column \"%s\"", column)
+		}
+		if contains(columns[:i], column) {
+			return fmt.Errorf("column \"%s\" is given more than once", column)
+		}
+	}
+
+	return nil
+}
+
+// update sets fields to values in the row whose ID matches id.
+// model is passed on to the TableResolver and may be nil.
+// Unless version is nil, the row must also have that version, or ErrStaleObject is returned.
+func (r SQLRepository[T]) update(ctx context.Context, id any, model any, fields []string, values []any, version *int64) error {
+	keyValues, err := r.keyValues(id)
+	if err != nil {
+		return err
+	}
+
+	templates, err := r.getTemplates(ctx, model)
 	if err != nil {
 		return err
 	}
 
-	sql, err := r.templates.GetUpdate(fields)
+	var sql string
+	if version != nil {
+		sql, err = templates.GetUpdateVersioned(fields, r.version)
+		keyValues = append(keyValues, *version)
+	} else {
+		sql, err = templates.GetUpdate(fields)
+	}
 	if err != nil {
 		return err
 	}
 
-	stmt, err := r.db.Preparex(sql)
+	stmt, err := r.db.PreparexContext(ctx, sql)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	allValues := append(values, id)
-	exec, err := stmt.Exec(allValues...)
+	allValues := append(values, keyValues...)
+	exec, err := stmt.ExecContext(ctx, allValues...)
 	if err != nil {
 		return err
 	}
@@ -120,6 +1012,9 @@ func (r SQLRepository[T]) Update(id any, model T) error {
 	if err != nil {
 		return err
 	}
+	if affected == 0 && version != nil {
+		return ErrStaleObject
+	}
 	if affected != 1 {
 		return fmt.Errorf("%d rows affected by UPDATE statement", affected)
 	}
@@ -129,14 +1024,43 @@ func (r SQLRepository[T]) Update(id any, model T) error {
 
 // Delete removes the row whose ID matches id.
 func (r SQLRepository[T]) Delete(id any) error {
-	sql := r.templates.GetDelete()
-	stmt, err := r.db.Preparex(sql)
+	return r.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext removes the row whose ID matches id.
+// If the model has a field tagged softdelete, the row is soft-deleted by setting that column
+// to the current time instead. See HardDeleteContext.
+func (r SQLRepository[T]) DeleteContext(ctx context.Context, id any) error {
+	return r.delete(ctx, id, r.softDelete == "")
+}
+
+// delete removes the row whose ID matches id if hard is set, or soft-deletes it otherwise.
+func (r SQLRepository[T]) delete(ctx context.Context, id any, hard bool) error {
+	keyValues, err := r.keyValues(id)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	sql, args := templates.GetDelete(), keyValues
+	if !hard {
+		sql, args = templates.GetSoftDelete(), append([]any{time.Now()}, keyValues...)
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	exec, err := stmt.Exec(id)
+	exec, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return err
 	}
@@ -152,18 +1076,65 @@ func (r SQLRepository[T]) Delete(id any) error {
 	return nil
 }
 
+// DeleteWhere removes all rows that match filter and returns the number of rows affected.
+// filter cannot be nil, so that a missing filter never empties the table.
+func (r SQLRepository[T]) DeleteWhere(filter Filter) (int64, error) {
+	return r.DeleteWhereContext(context.Background(), filter)
+}
+
+// DeleteWhereContext removes all rows that match filter and returns the number of rows affected.
+// filter cannot be nil, so that a missing filter never empties the table.
+// Like DeleteContext, the rows are soft-deleted if the model has a field tagged softdelete.
+func (r SQLRepository[T]) DeleteWhereContext(ctx context.Context, filter Filter) (int64, error) {
+	if filter == nil {
+		return 0, fmt.Errorf("filter cannot be nil")
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var sql string
+	var args []any
+	if r.softDelete != "" {
+		sql, args, err = templates.GetUpdateWhere([]string{r.softDelete}, filter)
+		args = append([]any{time.Now()}, args...)
+	} else {
+		sql, args, err = templates.GetDeleteWhere(filter)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, sql)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	exec, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return exec.RowsAffected()
+}
+
 // New creates and returns a new SQLRepository.
-func New[T any](db *sqlx.DB, config SQLRepositoryConfig) (*SQLRepository[T], error) {
+// The table must be set with WithTable or WithTableResolver, all other options are optional.
+// Unless WithDialect is given, the dialect is detected from db.DriverName().
+func New[T any](db *sqlx.DB, options ...Option) (*SQLRepository[T], error) {
 	if db == nil {
 		return nil, fmt.Errorf("db cannot be nil")
 	}
-	if config.table == "" {
-		return nil, fmt.Errorf("table cannot be empty")
-	}
 
-	idField := config.idField
-	if idField == "" {
-		idField = "id"
+	config, err := newSQLRepositoryConfig(db.DriverName(), options)
+	if err != nil {
+		return nil, err
 	}
 
 	structParser := newStructParser()
@@ -175,17 +1146,51 @@ func New[T any](db *sqlx.DB, config SQLRepositoryConfig) (*SQLRepository[T], err
 		return nil, err
 	}
 
-	paramGen := newSQLParamGen(config.dialect)
-	sqlGen := newSQLGenerator(paramGen)
-	statementGen, err := newSQLTemplates(sqlGen, config.table, idField, fields)
+	softDeleteFields, err := structParser.ParseTaggedFields(reflect.TypeOf(hack), "softdelete")
+	if err != nil {
+		return nil, err
+	}
+	if len(softDeleteFields) > 1 {
+		return nil, fmt.Errorf("%s has more than one field tagged \"softdelete\"", reflect.TypeOf(hack).Name())
+	}
+
+	var softDelete string
+	if len(softDeleteFields) == 1 {
+		softDelete = softDeleteFields[0]
+	}
+
+	version, err := parseVersionField[T](structParser)
 	if err != nil {
 		return nil, err
 	}
 
+	paramGen := newSQLParamGen(config.dialect)
+	quoter := newSQLIdentifierQuoter(config.dialect, config.quoting)
+	sqlGen := newSQLGenerator(paramGen, quoter, config.dialect)
+	templatesCache := newSQLTemplatesCache(func(tableName string) (sqlTemplates, error) {
+		return newSQLTemplates(sqlGen, tableName, config.idFields, fields, softDelete, version)
+	})
+
+	var statementGen sqlTemplates
+	if config.table != "" {
+		statementGen, err = templatesCache.Get(config.table)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &SQLRepository[T]{
-		db:           db,
-		templates:    statementGen,
-		structParser: structParser,
-		idField:      idField,
+		db:             db,
+		templates:      statementGen,
+		structParser:   structParser,
+		idFields:       config.idFields,
+		fields:         fields,
+		softDelete:     softDelete,
+		version:        version,
+		dialect:        config.dialect,
+		tableResolver:  config.tableResolver,
+		templatesCache: templatesCache,
+		timeout:        config.timeout,
+		cursor:         cursorCodec{key: config.cursorSecret},
 	}, nil
 }
@@ -0,0 +1,178 @@
+package dvbcrud
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SQLRepositoryConfig holds the settings used by New when creating an SQLRepository.
+// It is populated by the Option functions passed to New.
+type SQLRepositoryConfig struct {
+	dialect       Dialect
+	quoting       QuotingMode
+	table         string
+	tableResolver TableResolver
+	idFields      []string
+	timeout       time.Duration
+	cursorSecret  []byte
+}
+
+// TableResolver returns the table name to query in a single call, e.g. to pick a schema per tenant
+// or a partition per month. model is the model passed to the call, or nil if the call has none.
+// The returned name may be qualified with a schema.
+type TableResolver func(ctx context.Context, model any) (string, error)
+
+// Option configures an SQLRepository created by New.
+type Option func(config *SQLRepositoryConfig) error
+
+// WithTable sets the name of the table that the repository queries.
+// The name may be qualified with a schema, e.g. "app.Users".
+func WithTable(table string) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if table == "" {
+			return fmt.Errorf("table cannot be empty")
+		}
+		if err := validateTableName(table); err != nil {
+			return err
+		}
+		config.table = table
+		return nil
+	}
+}
+
+// WithTableResolver resolves the table name on each call instead of using a fixed one.
+// Templates are generated once per resolved table name and cached.
+func WithTableResolver(resolver TableResolver) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if resolver == nil {
+			return fmt.Errorf("table resolver cannot be nil")
+		}
+		config.tableResolver = resolver
+		return nil
+	}
+}
+
+// WithIDField sets the name of the ID column. Defaults to "id".
+func WithIDField(idField string) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if idField == "" {
+			return fmt.Errorf("idField cannot be empty")
+		}
+		if err := validateIdentifier(idField); err != nil {
+			return err
+		}
+		config.idFields = []string{idField}
+		return nil
+	}
+}
+
+// WithIDFields sets the names of the columns in a composite primary key, e.g. of a join table.
+// Repositories with a composite key insert the key columns along with the other columns,
+// and take a Key or a struct holding the key columns as the ID.
+func WithIDFields(idFields ...string) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if len(idFields) == 0 {
+			return fmt.Errorf("idFields cannot be empty")
+		}
+		for i, idField := range idFields {
+			if err := validateIdentifier(idField); err != nil {
+				return err
+			}
+			if contains(idFields[:i], idField) {
+				return fmt.Errorf("ID field \"%s\" is given more than once", idField)
+			}
+		}
+		config.idFields = idFields
+		return nil
+	}
+}
+
+// WithDialect sets the SQL dialect used when generating statements.
+// Defaults to the dialect registered for the driver name of the database.
+func WithDialect(dialect Dialect) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if dialect == nil {
+			return fmt.Errorf("dialect cannot be nil")
+		}
+		config.dialect = dialect
+		return nil
+	}
+}
+
+// WithQuoting sets when identifiers are quoted in generated statements.
+// Defaults to QuoteReserved.
+func WithQuoting(mode QuotingMode) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if mode < QuoteNever || mode > QuoteReserved {
+			return fmt.Errorf("unknown quoting mode %d", mode)
+		}
+		config.quoting = mode
+		return nil
+	}
+}
+
+// WithTimeout sets a default timeout for each call, which applies when the
+// context passed to the call has no deadline. A timeout of 0 disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout cannot be negative")
+		}
+		config.timeout = timeout
+		return nil
+	}
+}
+
+// WithCursorSecret sets the secret that ReadAfter signs its cursors with.
+// Defaults to a random secret, so cursors only stay valid within the same repository.
+// Set it when cursors must survive restarts or be shared between instances.
+func WithCursorSecret(secret []byte) Option {
+	return func(config *SQLRepositoryConfig) error {
+		if len(secret) == 0 {
+			return fmt.Errorf("cursor secret cannot be empty")
+		}
+		config.cursorSecret = secret
+		return nil
+	}
+}
+
+// newSQLRepositoryConfig applies options on top of the default configuration.
+// The dialect is detected from driverName unless it was set explicitly.
+func newSQLRepositoryConfig(driverName string, options []Option) (SQLRepositoryConfig, error) {
+	config := SQLRepositoryConfig{
+		quoting:  QuoteReserved,
+		idFields: []string{"id"},
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+		if err := option(&config); err != nil {
+			return SQLRepositoryConfig{}, err
+		}
+	}
+
+	if config.table == "" && config.tableResolver == nil {
+		return SQLRepositoryConfig{}, fmt.Errorf("table cannot be empty")
+	}
+
+	if config.dialect == nil {
+		dialect, err := DetectDialect(driverName)
+		if err != nil {
+			return SQLRepositoryConfig{}, err
+		}
+		config.dialect = dialect
+	}
+
+	if config.cursorSecret == nil {
+		secret, err := newCursorKey()
+		if err != nil {
+			return SQLRepositoryConfig{}, err
+		}
+		config.cursorSecret = secret
+	}
+
+	return config, nil
+}
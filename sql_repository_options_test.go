@@ -0,0 +1,218 @@
+package dvbcrud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewSQLRepositoryConfig(t *testing.T) {
+	config, err := newSQLRepositoryConfig("mysql", []Option{
+		WithDialect(PostgreSQL),
+		WithTable("Users"),
+		WithIDField("UserId"),
+	})
+	if err != nil {
+		t.Fatalf("Expected config, but got: %s", err)
+	}
+
+	if config.dialect != PostgreSQL || config.table != "Users" || !reflect.DeepEqual(config.idFields, []string{"UserId"}) {
+		t.Fatalf("Unexpected config %v", config)
+	}
+}
+
+func TestNewSQLRepositoryConfig_Defaults(t *testing.T) {
+	config, _ := newSQLRepositoryConfig("postgres", []Option{WithTable("Users")})
+
+	if config.dialect != PostgreSQL {
+		t.Fatalf("Expected dialect to be detected as PostgreSQL but got %s", config.dialect.Name())
+	}
+	if !reflect.DeepEqual(config.idFields, []string{"id"}) {
+		t.Fatalf("Expected idFields to default to [id] but got %v", config.idFields)
+	}
+	if config.quoting != QuoteReserved {
+		t.Fatalf("Expected quoting to default to QuoteReserved but got %d", config.quoting)
+	}
+}
+
+func TestNewSQLRepositoryConfig_MissingTable(t *testing.T) {
+	_, err := newSQLRepositoryConfig("mysql", []Option{})
+	expected := "table cannot be empty"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestNewSQLRepositoryConfig_OptionErr(t *testing.T) {
+	expected := fmt.Errorf("AnyError")
+	failing := func(config *SQLRepositoryConfig) error {
+		return expected
+	}
+
+	_, actual := newSQLRepositoryConfig("mysql", []Option{WithTable("Users"), failing})
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestNewSQLRepositoryConfig_UnknownDriver(t *testing.T) {
+	_, err := newSQLRepositoryConfig("AnyDriver", []Option{WithTable("Users")})
+	expected := "cannot detect dialect for driver \"AnyDriver\", use WithDialect or RegisterDriver"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestNewSQLRepositoryConfig_ExplicitDialectWins(t *testing.T) {
+	config, _ := newSQLRepositoryConfig("postgres", []Option{WithTable("Users"), WithDialect(SQLite)})
+
+	if config.dialect != SQLite {
+		t.Fatalf("Expected explicit dialect SQLite but got %s", config.dialect.Name())
+	}
+}
+
+func TestWithTable_Empty(t *testing.T) {
+	err := WithTable("")(&SQLRepositoryConfig{})
+	expected := "table cannot be empty"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestWithIDField_Empty(t *testing.T) {
+	err := WithIDField("")(&SQLRepositoryConfig{})
+	expected := "idField cannot be empty"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestWithDialect_Nil(t *testing.T) {
+	err := WithDialect(nil)(&SQLRepositoryConfig{})
+	expected := "dialect cannot be nil"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestWithQuoting_Unknown(t *testing.T) {
+	err := WithQuoting(-1)(&SQLRepositoryConfig{})
+	expected := "unknown quoting mode -1"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestWithTable_Invalid(t *testing.T) {
+	err := WithTable("Users; DROP TABLE Users")(&SQLRepositoryConfig{})
+
+	var identifierErr *InvalidIdentifierError
+	if !errors.As(err, &identifierErr) {
+		t.Fatalf("Expected InvalidIdentifierError but got %v", err)
+	}
+}
+
+func TestWithTable_SchemaQualified(t *testing.T) {
+	config := SQLRepositoryConfig{}
+	err := WithTable("app.Users")(&config)
+
+	if err != nil || config.table != "app.Users" {
+		t.Fatalf("Expected schema qualified table to be accepted, but got %v", err)
+	}
+}
+
+func TestWithIDField_Invalid(t *testing.T) {
+	err := WithIDField("app.id")(&SQLRepositoryConfig{})
+
+	var identifierErr *InvalidIdentifierError
+	if !errors.As(err, &identifierErr) {
+		t.Fatalf("Expected InvalidIdentifierError but got %v", err)
+	}
+}
+
+func TestNewSQLRepositoryConfig_TableResolverWithoutTable(t *testing.T) {
+	resolver := func(ctx context.Context, model any) (string, error) {
+		return "Users", nil
+	}
+
+	config, err := newSQLRepositoryConfig("mysql", []Option{WithTableResolver(resolver)})
+
+	if err != nil || config.tableResolver == nil {
+		t.Fatalf("Expected table resolver to replace table, but got %v", err)
+	}
+}
+
+func TestWithTableResolver_Nil(t *testing.T) {
+	err := WithTableResolver(nil)(&SQLRepositoryConfig{})
+	expected := "table resolver cannot be nil"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestWithTimeout_Negative(t *testing.T) {
+	err := WithTimeout(-time.Second)(&SQLRepositoryConfig{})
+	expected := "timeout cannot be negative"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestWithCursorSecret_Empty(t *testing.T) {
+	err := WithCursorSecret(nil)(&SQLRepositoryConfig{})
+	expected := "cursor secret cannot be empty"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+func TestNewSQLRepositoryConfig_RandomCursorSecret(t *testing.T) {
+	first, _ := newSQLRepositoryConfig("mysql", []Option{WithTable("Users")})
+	second, _ := newSQLRepositoryConfig("mysql", []Option{WithTable("Users")})
+
+	if len(first.cursorSecret) == 0 || string(first.cursorSecret) == string(second.cursorSecret) {
+		t.Fatalf("Expected distinct random cursor secrets")
+	}
+}
+
+func TestWithIDFields(t *testing.T) {
+	config := SQLRepositoryConfig{}
+	err := WithIDFields("user_id", "group_id")(&config)
+
+	if err != nil || !reflect.DeepEqual(config.idFields, []string{"user_id", "group_id"}) {
+		t.Fatalf("Expected ID fields [user_id group_id] but got %v (%v)", config.idFields, err)
+	}
+}
+
+func TestWithIDFields_Invalid(t *testing.T) {
+	tests := map[string][]string{
+		"idFields cannot be empty":                     {},
+		"ID field \"user_id\" is given more than once": {"user_id", "user_id"},
+	}
+
+	for expected, idFields := range tests {
+		err := WithIDFields(idFields...)(&SQLRepositoryConfig{})
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+		}
+	}
+
+	err := WithIDFields("user_id", "group id")(&SQLRepositoryConfig{})
+	var identifierErr *InvalidIdentifierError
+	if !errors.As(err, &identifierErr) {
+		t.Fatalf("Expected InvalidIdentifierError but got %v", err)
+	}
+}
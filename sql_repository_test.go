@@ -1,7 +1,9 @@
 package dvbcrud
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -25,17 +27,22 @@ type parseCrashingType struct {
 func newMock[T any]() (*SQLRepository[T], *sql.DB, sqlmock.Sqlmock, error) {
 	mockDB, mock, err := sqlmock.New()
 	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
-	config := SQLRepositoryConfig{
-		dialect: MySQL,
-		table:   "Users",
-		idField: "UserId",
-		fields:  []string{"Name", "Surname", "Birthdate", "CreatedAt"},
-	}
-	repo, _ := New[T](sqlxDb, config)
+	repo, _ := New[T](sqlxDb,
+		WithDialect(MySQL),
+		WithTable("Users"),
+		WithIDField("UserId"))
 	repo.templates = sqlTemplatesMock{}
 	return repo, mockDB, mock, err
 }
 
+// newDialectMock creates a repository that generates real statements for dialect,
+// with a mock that matches the statements exactly.
+func newDialectMock[T any](dialect Dialect, options ...Option) (*SQLRepository[T], sqlmock.Sqlmock, func() error) {
+	mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	repo, _ := New[T](sqlx.NewDb(mockDB, "sqlmock"), append([]Option{WithDialect(dialect)}, options...)...)
+	return repo, mock, mockDB.Close
+}
+
 func TestSqlRepository_Create(t *testing.T) {
 	repo, mockDB, mock, _ := newMock[repoTestUser]()
 	defer mockDB.Close()
@@ -73,6 +80,7 @@ func TestSQLRepository_Create_ParsePropertiesErr(t *testing.T) {
 	}
 	repo := SQLRepository[any]{
 		structParser: parserMock,
+		idFields:     []string{"id"},
 	}
 
 	actual := repo.Create("AnyModel")
@@ -96,6 +104,7 @@ func TestSQLRepository_Create_GetSqlErr(t *testing.T) {
 	}
 	repo := SQLRepository[any]{
 		structParser: parserMock,
+		idFields:     []string{"id"},
 		templates:    templatesMock,
 	}
 
@@ -373,7 +382,7 @@ func TestSqlRepository_Update(t *testing.T) {
 		WithArgs(user.Name, user.Surname, user.Birthdate, user.CreatedAt, user.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err := repo.Update(1, user)
+	err := repo.Update(1, &user)
 	if err != nil {
 		t.Fatalf("Expected Update to succeed, but got: %s", err)
 	}
@@ -388,9 +397,11 @@ func TestSQLRepository_Update_ParsePropertiesErr(t *testing.T) {
 	}
 	repo := SQLRepository[any]{
 		structParser: parserMock,
+		idFields:     []string{"id"},
 	}
 
-	actual := repo.Update(1, "AnyModel")
+	model := any("AnyModel")
+	actual := repo.Update(1, &model)
 
 	if actual != expected {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -411,10 +422,12 @@ func TestSQLRepository_Update_GetSqlErr(t *testing.T) {
 	}
 	repo := SQLRepository[any]{
 		structParser: parserMock,
+		idFields:     []string{"id"},
 		templates:    templatesMock,
 	}
 
-	actual := repo.Update(1, "AnyModel")
+	model := any("AnyModel")
+	actual := repo.Update(1, &model)
 
 	if actual != expected {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -433,7 +446,7 @@ func TestSqlRepository_Update_PrepareErr(t *testing.T) {
 	mock.ExpectPrepare("AnyUpdate").
 		WillReturnError(expected)
 
-	actual := repo.Update(1, repoTestUser{})
+	actual := repo.Update(1, &repoTestUser{})
 
 	if actual != expected {
 		t.Fatalf("Expected \"%s\" but got \"%s\" instead", expected, actual)
@@ -453,7 +466,7 @@ func TestSqlRepository_Update_ExecErr(t *testing.T) {
 		ExpectExec().
 		WillReturnError(expected)
 
-	actual := repo.Update(1, repoTestUser{})
+	actual := repo.Update(1, &repoTestUser{})
 
 	if actual != expected {
 		t.Fatalf("Expected \"%s\" but got \"%s\" instead", expected, actual)
@@ -475,7 +488,7 @@ func TestSqlRepository_Update_RowsAffectedErr(t *testing.T) {
 		WithArgs(user.Name, user.Surname, user.Birthdate, user.CreatedAt, 1).
 		WillReturnResult(sqlmock.NewErrorResult(expected))
 
-	actual := repo.Update(1, repoTestUser{})
+	actual := repo.Update(1, &repoTestUser{})
 
 	if actual != expected {
 		t.Fatalf("Expected \"%s\" but got \"%s\" instead", expected, actual)
@@ -497,7 +510,7 @@ func TestSqlRepository_Update_OtherThanOneRowAffected(t *testing.T) {
 		WithArgs(user.Name, user.Surname, user.Birthdate, user.CreatedAt, 1).
 		WillReturnResult(sqlmock.NewResult(1, 2))
 
-	actual := repo.Update(1, repoTestUser{})
+	actual := repo.Update(1, &repoTestUser{})
 
 	if actual.Error() != expected {
 		t.Fatalf("Expected \"%s\" but got \"%s\" instead", expected, actual)
@@ -609,13 +622,10 @@ func TestNew(t *testing.T) {
 	mockDB, _, _ := sqlmock.New()
 	defer mockDB.Close()
 	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
-	config := SQLRepositoryConfig{
-		dialect: MySQL,
-		table:   "Users",
-		idField: "UserId",
-		fields:  []string{"Name", "Surname", "Birthdate", "CreatedAt"},
-	}
-	repo, _ := New[repoTestUser](sqlxDb, config)
+	repo, _ := New[repoTestUser](sqlxDb,
+		WithDialect(MySQL),
+		WithTable("Users"),
+		WithIDField("UserId"))
 
 	if repo == nil {
 		t.Fatalf("Expected a repo, but got nil instead")
@@ -623,13 +633,10 @@ func TestNew(t *testing.T) {
 }
 
 func TestNew_NilDb(t *testing.T) {
-	config := SQLRepositoryConfig{
-		dialect: MySQL,
-		table:   "Users",
-		idField: "UserId",
-		fields:  []string{"Name", "Surname", "Birthdate", "CreatedAt"},
-	}
-	_, err := New[repoTestUser](nil, config)
+	_, err := New[repoTestUser](nil,
+		WithDialect(MySQL),
+		WithTable("Users"),
+		WithIDField("UserId"))
 	if err == nil {
 		t.Fatalf("Expected error on nil db")
 	}
@@ -644,13 +651,9 @@ func TestNew_EmptyTableName(t *testing.T) {
 	mockDB, _, _ := sqlmock.New()
 	defer mockDB.Close()
 	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
-	config := SQLRepositoryConfig{
-		dialect: MySQL,
-		table:   "",
-		idField: "UserId",
-		fields:  []string{"Name", "Surname", "Birthdate", "CreatedAt"},
-	}
-	_, err := New[repoTestUser](sqlxDb, config)
+	_, err := New[repoTestUser](sqlxDb,
+		WithDialect(MySQL),
+		WithIDField("UserId"))
 	if err == nil {
 		t.Fatalf("Expected error on empty table name")
 	}
@@ -661,22 +664,928 @@ func TestNew_EmptyTableName(t *testing.T) {
 	}
 }
 
-func TestNew_EmptyIdFieldName(t *testing.T) {
+func TestNew_DefaultIdFieldName(t *testing.T) {
 	mockDB, _, _ := sqlmock.New()
 	defer mockDB.Close()
 	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
-	config := SQLRepositoryConfig{
-		dialect: MySQL,
-		table:   "Users",
-		idField: "",
-		fields:  []string{"Name", "Surname", "Birthdate", "CreatedAt"},
-	}
-	repo, _ := New[repoTestUser](sqlxDb, config)
+	repo, _ := New[repoTestUser](sqlxDb,
+		WithDialect(MySQL),
+		WithTable("Users"))
 	if repo == nil {
-		t.Fatalf("Expected a repo on empty idField")
+		t.Fatalf("Expected a repo without idField")
+	}
+
+	if !reflect.DeepEqual(repo.idFields, []string{"id"}) {
+		t.Fatalf("Expected idFields to be [id]")
+	}
+}
+
+func TestSqlRepository_TableResolver(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
+	resolver := func(ctx context.Context, model any) (string, error) {
+		return "tenant_acme.Users", nil
+	}
+	repo, _ := New[repoTestUser](sqlxDb,
+		WithDialect(MySQL),
+		WithTableResolver(resolver),
+		WithIDField("UserId"))
+
+	mock.ExpectPrepare("DELETE FROM tenant_acme.Users WHERE UserId = \\?").
+		ExpectExec().
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Delete(1)
+	if err != nil {
+		t.Fatalf("Expected Delete to succeed, but got: %s", err)
+	}
+	if _, ok := repo.templatesCache.templates["tenant_acme.Users"]; !ok {
+		t.Fatalf("Expected templates to be cached for the resolved table")
+	}
+}
+
+func TestSqlRepository_TableResolverErr(t *testing.T) {
+	expected := fmt.Errorf("AnyError")
+	repo := SQLRepository[repoTestUser]{
+		idFields: []string{"UserId"},
+		tableResolver: func(ctx context.Context, model any) (string, error) {
+			return "", expected
+		},
+	}
+
+	_, actual := repo.Read(1)
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSqlRepository_TableResolverInvalidTable(t *testing.T) {
+	repo := SQLRepository[repoTestUser]{
+		tableResolver: func(ctx context.Context, model any) (string, error) {
+			return "Users; DROP TABLE Users", nil
+		},
+	}
+
+	_, err := repo.ReadAll()
+
+	if _, ok := err.(*InvalidIdentifierError); !ok {
+		t.Fatalf("Expected InvalidIdentifierError but got %v", err)
+	}
+}
+
+func TestSqlRepository_WithTimeout(t *testing.T) {
+	repo := SQLRepository[repoTestUser]{timeout: time.Minute}
+
+	ctx, cancel := repo.withTimeout(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatalf("Expected default timeout to set a deadline")
+	}
+}
+
+func TestSqlRepository_WithTimeout_KeepsDeadline(t *testing.T) {
+	repo := SQLRepository[repoTestUser]{timeout: time.Minute}
+	expected := time.Now().Add(time.Hour)
+	parent, parentCancel := context.WithDeadline(context.Background(), expected)
+	defer parentCancel()
+
+	ctx, cancel := repo.withTimeout(parent)
+	defer cancel()
+
+	actual, _ := ctx.Deadline()
+	if !actual.Equal(expected) {
+		t.Fatalf("Expected deadline %v but got %v", expected, actual)
+	}
+}
+
+func TestSqlRepository_WithTimeout_Disabled(t *testing.T) {
+	repo := SQLRepository[repoTestUser]{}
+
+	ctx, cancel := repo.withTimeout(context.Background())
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatalf("Expected no deadline without a default timeout")
+	}
+}
+
+func TestSqlRepository_ReadContext_Cancelled(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectMock: func() string {
+			return "AnySelect"
+		},
+	}
+	mock.ExpectPrepare("AnySelect").
+		ExpectQuery().
+		WithArgs(1).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"UserId"}).AddRow(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := repo.ReadContext(ctx, 1)
+
+	if err == nil {
+		t.Fatalf("Expected ReadContext to fail when the context is done")
+	}
+}
+
+func TestSqlRepository_CreateReturning_ReturningClause(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.dialect = PostgreSQL
+	repo.templates = sqlTemplatesMock{
+		GetInsertRetMock: func(fields []string) (string, error) {
+			return "AnyInsertReturning", nil
+		},
+	}
+	user := repoTestUser{
+		Name:      "AnyName",
+		Surname:   "AnySurname",
+		Birthdate: time.Now(),
+		CreatedAt: time.Now(),
+	}
+
+	rows := sqlmock.NewRows([]string{"UserId", "Name", "Surname", "Birthdate", "CreatedAt"}).
+		AddRow(7, user.Name, user.Surname, user.Birthdate, user.CreatedAt)
+	mock.ExpectPrepare("AnyInsertReturning").
+		ExpectQuery().
+		WithArgs(user.Name, user.Surname, user.Birthdate, user.CreatedAt).
+		WillReturnRows(rows)
+
+	actual, err := repo.CreateReturning(user)
+	if err != nil {
+		t.Fatalf("Expected CreateReturning to succeed, but got: %s", err)
+	}
+
+	user.ID = 7
+	if !reflect.DeepEqual(&user, actual) {
+		t.Fatalf("Expected %v but got %v", user, actual)
+	}
+}
+
+func TestSqlRepository_CreateReturning_LastInsertID(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetInsertRetMock: func(fields []string) (string, error) {
+			return "AnyInsert", nil
+		},
+	}
+	user := repoTestUser{Name: "AnyName"}
+
+	mock.ExpectPrepare("AnyInsert").
+		ExpectExec().
+		WithArgs(user.Name, user.Surname, user.Birthdate, user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+
+	actual, err := repo.CreateReturning(user)
+	if err != nil {
+		t.Fatalf("Expected CreateReturning to succeed, but got: %s", err)
+	}
+
+	if actual.ID != 42 || actual.Name != user.Name {
+		t.Fatalf("Expected generated ID 42 on the model but got %v", actual)
+	}
+}
+
+func TestSqlRepository_CreateReturning_LastInsertIDErr(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetInsertRetMock: func(fields []string) (string, error) {
+			return "AnyInsert", nil
+		},
+	}
+	expected := fmt.Errorf("any error")
+	mock.ExpectPrepare("AnyInsert").
+		ExpectExec().
+		WillReturnResult(sqlmock.NewErrorResult(expected))
+
+	_, actual := repo.CreateReturning(repoTestUser{})
+
+	if actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\" instead", expected, actual)
+	}
+}
+
+func TestSqlRepository_CreateReturning_GetSqlErr(t *testing.T) {
+	expected := fmt.Errorf("AnyError")
+	parserMock := structParserMock{
+		ParsePropertiesMock: func(model any, idFieldName string) ([]string, []any, error) {
+			return []string{}, []any{}, nil
+		},
+	}
+	templatesMock := sqlTemplatesMock{
+		GetInsertRetMock: func(fields []string) (string, error) {
+			return "", expected
+		},
+	}
+	repo := SQLRepository[any]{
+		structParser: parserMock,
+		idFields:     []string{"id"},
+		templates:    templatesMock,
+	}
+
+	_, actual := repo.CreateReturning("AnyModel")
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestAssignInt64_IncompatibleType(t *testing.T) {
+	var dest struct{}
+	err := assignInt64(&dest, 1)
+	expected := "cannot assign generated ID to a field of type struct {}"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, err)
+	}
+}
+
+// outPassthroughConverter lets sql.Out arguments through to sqlmock.
+type outPassthroughConverter struct{}
+
+func (outPassthroughConverter) ConvertValue(v any) (driver.Value, error) {
+	if out, ok := v.(sql.Out); ok {
+		return out, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func TestSqlRepository_CreateReturning_ReturningInto(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New(sqlmock.ValueConverterOption(outPassthroughConverter{}))
+	defer mockDB.Close()
+	repo, _ := New[repoTestUser](sqlx.NewDb(mockDB, "godror"),
+		WithTable("Users"),
+		WithIDField("UserId"))
+	repo.templates = sqlTemplatesMock{
+		GetInsertRetMock: func(fields []string) (string, error) {
+			return "AnyInsertReturningInto", nil
+		},
+	}
+	user := repoTestUser{Name: "AnyName"}
+
+	mock.ExpectPrepare("AnyInsertReturningInto").
+		ExpectExec().
+		WithArgs(user.Name, user.Surname, user.Birthdate, user.CreatedAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := repo.CreateReturning(user)
+	if err != nil {
+		t.Fatalf("Expected CreateReturning to succeed, but got: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %s", err)
+	}
+}
+
+type batchTestDialect struct {
+	Dialect
+}
+
+func (batchTestDialect) BatchLimits() BatchLimits { return BatchLimits{MaxParameters: 8} }
+
+func TestSqlRepository_CreateMany(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.dialect = batchTestDialect{MySQL}
+	var batches []int
+	repo.templates = sqlTemplatesMock{
+		GetInsertManyMock: func(fields []string, rows int) (string, error) {
+			batches = append(batches, rows)
+			return fmt.Sprintf("AnyInsertMany%d", rows), nil
+		},
+	}
+	users := []repoTestUser{{Name: "AnyName1"}, {Name: "AnyName2"}, {Name: "AnyName3"}}
+
+	mock.ExpectPrepare("AnyInsertMany2").
+		ExpectExec().
+		WithArgs(users[0].Name, users[0].Surname, users[0].Birthdate, users[0].CreatedAt,
+			users[1].Name, users[1].Surname, users[1].Birthdate, users[1].CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectPrepare("AnyInsertMany1").
+		ExpectExec().
+		WithArgs(users[2].Name, users[2].Surname, users[2].Birthdate, users[2].CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	affected, err := repo.CreateMany(users)
+	if err != nil {
+		t.Fatalf("Expected CreateMany to succeed, but got: %s", err)
+	}
+
+	if affected != 3 {
+		t.Fatalf("Expected 3 rows affected but got %d", affected)
+	}
+	if !reflect.DeepEqual(batches, []int{2, 1}) {
+		t.Fatalf("Expected batches of [2 1] but got %v", batches)
+	}
+}
+
+func TestSqlRepository_CreateMany_TableResolver(t *testing.T) {
+	resolver := func(ctx context.Context, model any) (string, error) {
+		return "events_" + model.(repoTestUser).CreatedAt.Format("2006_01"), nil
+	}
+	repo, mock, closeDB := newDialectMock[repoTestUser](PostgreSQL,
+		WithTableResolver(resolver),
+		WithIDField("UserId"))
+	defer closeDB()
+	september := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+	october := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	users := []repoTestUser{
+		{Name: "AnyName1", CreatedAt: september},
+		{Name: "AnyName2", CreatedAt: october},
+		{Name: "AnyName3", CreatedAt: september},
+	}
+
+	mock.ExpectPrepare("INSERT INTO events_2026_09 (\"Name\", \"Surname\", \"Birthdate\", \"CreatedAt\") "+
+		"VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)").
+		ExpectExec().
+		WithArgs(users[0].Name, users[0].Surname, users[0].Birthdate, users[0].CreatedAt,
+			users[2].Name, users[2].Surname, users[2].Birthdate, users[2].CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectPrepare("INSERT INTO events_2026_10 (\"Name\", \"Surname\", \"Birthdate\", \"CreatedAt\") "+
+		"VALUES ($1, $2, $3, $4)").
+		ExpectExec().
+		WithArgs(users[1].Name, users[1].Surname, users[1].Birthdate, users[1].CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	affected, err := repo.CreateMany(users)
+
+	if err != nil || affected != 3 {
+		t.Fatalf("Expected 3 rows affected but got %d (%v)", affected, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSqlRepository_CreateMany_SQLServerBatchLimit(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.dialect = SQLServer
+	var batches []int
+	repo.templates = sqlTemplatesMock{
+		GetInsertManyMock: func(fields []string, rows int) (string, error) {
+			batches = append(batches, rows)
+			return fmt.Sprintf("AnyInsertMany%d", rows), nil
+		},
+	}
+	// 525 rows of 4 columns would be 2100 parameters, which leaves no room for sp_executesql's own two
+	users := make([]repoTestUser, 525)
+
+	mock.ExpectPrepare("AnyInsertMany524").
+		ExpectExec().
+		WillReturnResult(sqlmock.NewResult(0, 524))
+	mock.ExpectPrepare("AnyInsertMany1").
+		ExpectExec().
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	affected, err := repo.CreateMany(users)
+
+	if err != nil || affected != 525 {
+		t.Fatalf("Expected 525 rows affected but got %d (%v)", affected, err)
+	}
+	if !reflect.DeepEqual(batches, []int{524, 1}) {
+		t.Fatalf("Expected batches of [524 1] but got %v", batches)
+	}
+}
+
+func TestSqlRepository_CreateMany_Empty(t *testing.T) {
+	repo := SQLRepository[repoTestUser]{}
+
+	affected, err := repo.CreateMany(nil)
+
+	if affected != 0 || err != nil {
+		t.Fatalf("Expected nothing to happen but got %d, %v", affected, err)
+	}
+}
+
+func TestSqlRepository_CreateMany_ExecErr(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetInsertManyMock: func(fields []string, rows int) (string, error) {
+			return "AnyInsertMany", nil
+		},
+	}
+	expected := fmt.Errorf("any error")
+	mock.ExpectPrepare("AnyInsertMany").
+		ExpectExec().
+		WillReturnError(expected)
+
+	_, actual := repo.CreateMany([]repoTestUser{{}})
+
+	if actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\" instead", expected, actual)
+	}
+}
+
+func TestSqlRepository_BatchSize(t *testing.T) {
+	tests := map[Dialect]int{
+		PostgreSQL: 16383,
+		SQLServer:  524,
+		ODBC:       1,
 	}
 
-	if repo.idField != "id" {
-		t.Fatalf("Expected idField to be \"id\"")
+	for dialect, expected := range tests {
+		repo := SQLRepository[repoTestUser]{dialect: dialect}
+		actual := repo.batchSize(4)
+		if actual != expected {
+			t.Fatalf("Expected batch size %d for %s but got %d", expected, dialect.Name(), actual)
+		}
+	}
+}
+
+func TestSqlRepository_Upsert(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetUpsertMock: func(fields []string, conflictFields []string, updateFields []string) (string, error) {
+			if !reflect.DeepEqual(fields, []string{"UserId", "Name", "Surname", "Birthdate", "CreatedAt"}) {
+				t.Fatalf("Expected ID to be inserted when it is the conflict column, but got %v", fields)
+			}
+			if !reflect.DeepEqual(conflictFields, []string{"UserId"}) {
+				t.Fatalf("Expected conflict on UserId but got %v", conflictFields)
+			}
+			if !reflect.DeepEqual(updateFields, []string{"Name", "Surname", "Birthdate", "CreatedAt"}) {
+				t.Fatalf("Expected all other columns to be updated but got %v", updateFields)
+			}
+			return "AnyUpsert", nil
+		},
+	}
+	user := repoTestUser{ID: 1, Name: "AnyName"}
+
+	mock.ExpectPrepare("AnyUpsert").
+		ExpectExec().
+		WithArgs(user.ID, user.Name, user.Surname, user.Birthdate, user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Upsert(user)
+	if err != nil {
+		t.Fatalf("Expected Upsert to succeed, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_UpsertColumns(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetUpsertMock: func(fields []string, conflictFields []string, updateFields []string) (string, error) {
+			if contains(fields, "UserId") {
+				t.Fatalf("Expected ID not to be inserted but got %v", fields)
+			}
+			if !reflect.DeepEqual(updateFields, []string{"Surname"}) {
+				t.Fatalf("Expected only Surname to be updated but got %v", updateFields)
+			}
+			return "AnyUpsert", nil
+		},
+	}
+	user := repoTestUser{Name: "AnyName", Surname: "AnySurname"}
+
+	mock.ExpectPrepare("AnyUpsert").
+		ExpectExec().
+		WithArgs(user.Name, user.Surname, user.Birthdate, user.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.UpsertColumns(user, []string{"Name"}, []string{"Surname"})
+	if err != nil {
+		t.Fatalf("Expected UpsertColumns to succeed, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_Upsert_UnknownConflictColumn(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "unknown conflict column \"AnyColumn\""
+
+	actual := repo.Upsert(repoTestUser{}, "AnyColumn")
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_UpsertColumns_ConflictColumnUpdated(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "cannot update column \"Name\" in upsert"
+
+	actual := repo.UpsertColumns(repoTestUser{}, []string{"Name"}, []string{"Name"})
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_UpdateColumns(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetUpdateMock: func(fields []string) (string, error) {
+			if !reflect.DeepEqual(fields, []string{"Surname", "Name"}) {
+				t.Fatalf("Expected only the given columns but got %v", fields)
+			}
+			return "AnyUpdate", nil
+		},
+	}
+	user := repoTestUser{ID: 1, Name: "AnyName", Surname: "AnySurname"}
+
+	mock.ExpectPrepare("AnyUpdate").
+		ExpectExec().
+		WithArgs(user.Surname, user.Name, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.UpdateColumns(1, &user, "Surname", "Name")
+	if err != nil {
+		t.Fatalf("Expected UpdateColumns to succeed, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_Patch(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetUpdateMock: func(fields []string) (string, error) {
+			if !reflect.DeepEqual(fields, []string{"Name", "Surname"}) {
+				t.Fatalf("Expected sorted patch columns but got %v", fields)
+			}
+			return "AnyUpdate", nil
+		},
+	}
+
+	mock.ExpectPrepare("AnyUpdate").
+		ExpectExec().
+		WithArgs("AnyName", "AnySurname", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Patch(1, map[string]any{"Surname": "AnySurname", "Name": "AnyName"})
+	if err != nil {
+		t.Fatalf("Expected Patch to succeed, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_Patch_InvalidColumns(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	tests := map[string]map[string]any{
+		"no columns to update":                  {},
+		"cannot update ID column \"UserId\"":    {"UserId": 2},
+		"unknown column \"name; DROP TABLE x\"": {"name; DROP TABLE x": 1},
+	}
+
+	for expected, fields := range tests {
+		actual := repo.Patch(1, fields)
+		if actual == nil || actual.Error() != expected {
+			t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+		}
+	}
+}
+
+func TestSqlRepository_UpdateColumns_Duplicate(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "column \"Name\" is given more than once"
+
+	actual := repo.UpdateColumns(1, &repoTestUser{}, "Name", "Name")
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_ReadWhere(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnySelectWhere", []any{"AnyName"}, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectWhere").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).
+			AddRow(1, "AnyName").
+			AddRow(2, "AnyName"))
+
+	actual, err := repo.ReadWhere(Eq("Name", "AnyName"))
+	if err != nil {
+		t.Fatalf("Expected ReadWhere to succeed, but got: %s", err)
+	}
+	if len(actual) != 2 || actual[1].ID != 2 {
+		t.Fatalf("Expected 2 users but got %v", actual)
+	}
+}
+
+func TestSqlRepository_ReadWhere_GetSqlErr(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := fmt.Errorf("AnyError")
+	repo.templates = sqlTemplatesMock{
+		GetSelectWhereMock: func(filter Filter) (string, []any, error) {
+			return "", nil, expected
+		},
+	}
+
+	_, actual := repo.ReadWhere(Eq("Name", "AnyName"))
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSqlRepository_CountWhere(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetCountWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnyCount", []any{"AnyName"}, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnyCount").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(42))
+
+	actual, err := repo.CountWhere(Eq("Name", "AnyName"))
+	if err != nil || actual != 42 {
+		t.Fatalf("Expected 42 but got %d (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_DeleteWhere(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetDeleteWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnyDeleteWhere", []any{"AnyName"}, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnyDeleteWhere").
+		ExpectExec().
+		WithArgs("AnyName").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	actual, err := repo.DeleteWhere(Eq("Name", "AnyName"))
+	if err != nil || actual != 3 {
+		t.Fatalf("Expected 3 rows affected but got %d (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_DeleteWhere_NilFilter(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "filter cannot be nil"
+
+	_, actual := repo.DeleteWhere(nil)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_ReadPage(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectPageMock: func(filter Filter, orderBy []OrderBy, limit int, offset int) (string, []any, error) {
+			if limit != 2 || offset != 4 || len(orderBy) != 1 || orderBy[0] != Desc("Name") {
+				t.Fatalf("Unexpected page %d, %d, %v", limit, offset, orderBy)
+			}
+			return "AnySelectPage", []any{"AnyName"}, nil
+		},
+		GetCountWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnyCount", []any{"AnyName"}, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectPage").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).
+			AddRow(5, "AnyName").
+			AddRow(6, "AnyName"))
+	mock.ExpectPrepare("AnyCount").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(9))
+
+	actual, err := repo.ReadPage(PageRequest{
+		Limit:     2,
+		Offset:    4,
+		OrderBy:   []OrderBy{Desc("Name")},
+		Filter:    Eq("Name", "AnyName"),
+		WithTotal: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected ReadPage to succeed, but got: %s", err)
+	}
+	if len(actual.Items) != 2 || actual.Total == nil || *actual.Total != 9 {
+		t.Fatalf("Expected 2 users of 9 but got %v", actual)
+	}
+}
+
+func TestSqlRepository_ReadPage_WithoutTotal(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectPageMock: func(filter Filter, orderBy []OrderBy, limit int, offset int) (string, []any, error) {
+			return "AnySelectPage", nil, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectPage").
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"UserId"}).AddRow(1))
+
+	actual, err := repo.ReadPage(PageRequest{Limit: 1})
+	if err != nil || actual.Total != nil {
+		t.Fatalf("Expected page without total but got %v (%v)", actual, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Expected no count query, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_ReadAfter(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	var seeks []Filter
+	repo.templates = sqlTemplatesMock{
+		GetSelectPageMock: func(filter Filter, orderBy []OrderBy, limit int, offset int) (string, []any, error) {
+			if limit != 3 || offset != 0 || !reflect.DeepEqual(orderBy, []OrderBy{Asc("Name"), Asc("UserId")}) {
+				t.Fatalf("Unexpected page %d, %d, %v", limit, offset, orderBy)
+			}
+			seeks = append(seeks, filter)
+			return "AnySelectPage", nil, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectPage").
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).
+			AddRow(1, "Ann").
+			AddRow(2, "Bob").
+			AddRow(3, "Cid"))
+	mock.ExpectPrepare("AnySelectPage").
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).
+			AddRow(3, "Cid"))
+
+	first, err := repo.ReadAfter("", 2, Asc("Name"))
+	if err != nil || len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("Expected 2 users and a cursor but got %v (%v)", first, err)
+	}
+
+	second, err := repo.ReadAfter(first.NextCursor, 2, Asc("Name"))
+	if err != nil || len(second.Items) != 1 || second.NextCursor != "" {
+		t.Fatalf("Expected the last user without a cursor but got %v (%v)", second, err)
+	}
+
+	expected := seekFilter([]OrderBy{Asc("Name"), Asc("UserId")}, []any{"Bob", uint64(2)}, true)
+	if seeks[0] != nil || !reflect.DeepEqual(seeks[1], expected) {
+		t.Fatalf("Expected seek %v but got %v", expected, seeks)
+	}
+}
+
+func TestSqlRepository_ReadAfter_InvalidCursor(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+
+	_, actual := repo.ReadAfter("AnyCursor", 2)
+
+	if actual != ErrInvalidCursor {
+		t.Fatalf("Expected %v but got %v", ErrInvalidCursor, actual)
+	}
+}
+
+func TestSqlRepository_ReadAfter_UnknownColumn(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "unknown column \"Age\" in order by"
+
+	_, actual := repo.ReadAfter("", 2, Desc("Age"))
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_Each(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnySelectWhere", nil, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectWhere").
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).
+			AddRow(1, "Ann").
+			AddRow(2, "Bob")).
+		RowsWillBeClosed()
+
+	var names []string
+	err := repo.Each(func(user repoTestUser) error {
+		names = append(names, user.Name)
+		return nil
+	})
+
+	if err != nil || !reflect.DeepEqual(names, []string{"Ann", "Bob"}) {
+		t.Fatalf("Expected [Ann Bob] but got %v (%v)", names, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Expected rows to be closed, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_EachWhere_Stop(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnySelectWhere", []any{"A%"}, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectWhere").
+		ExpectQuery().
+		WithArgs("A%").
+		WillReturnRows(sqlmock.NewRows([]string{"UserId", "Name"}).
+			AddRow(1, "Ann").
+			AddRow(2, "Amy")).
+		RowsWillBeClosed()
+
+	calls := 0
+	err := repo.EachWhere(Like("Name", "A%"), func(user repoTestUser) error {
+		calls++
+		return ErrStop
+	})
+
+	if err != nil || calls != 1 {
+		t.Fatalf("Expected iteration to stop after 1 call, but got %d calls (%v)", calls, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Expected rows to be closed, but got: %s", err)
+	}
+}
+
+func TestSqlRepository_Each_CallbackErr(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := fmt.Errorf("AnyError")
+	repo.templates = sqlTemplatesMock{
+		GetSelectWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnySelectWhere", nil, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectWhere").
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"UserId"}).AddRow(1).AddRow(2))
+
+	actual := repo.Each(func(user repoTestUser) error {
+		return expected
+	})
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSqlRepository_Each_StructScanErr(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetSelectWhereMock: func(filter Filter) (string, []any, error) {
+			return "AnySelectWhere", nil, nil
+		},
+	}
+
+	mock.ExpectPrepare("AnySelectWhere").
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"UnknownColumn"}).AddRow(1))
+
+	actual := repo.Each(func(user repoTestUser) error {
+		t.Fatalf("Expected no call")
+		return nil
+	})
+
+	if actual == nil {
+		t.Fatalf("Expected scan error but got nil")
 	}
 }
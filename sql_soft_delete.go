@@ -0,0 +1,75 @@
+package dvbcrud
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReadWithDeleted fetches the row whose ID matches id, even if it is soft-deleted.
+func (r SQLRepository[T]) ReadWithDeleted(id any) (*T, error) {
+	return r.ReadWithDeletedContext(context.Background(), id)
+}
+
+// ReadWithDeletedContext fetches the row whose ID matches id, even if it is soft-deleted.
+// Without a field tagged softdelete, it is the same as ReadContext.
+func (r SQLRepository[T]) ReadWithDeletedContext(ctx context.Context, id any) (*T, error) {
+	return r.read(ctx, id, true)
+}
+
+// HardDelete removes the row whose ID matches id, even if the model has a field tagged softdelete.
+func (r SQLRepository[T]) HardDelete(id any) error {
+	return r.HardDeleteContext(context.Background(), id)
+}
+
+// HardDeleteContext removes the row whose ID matches id, even if the model has a field tagged softdelete.
+// Soft-deleted rows are removed too.
+func (r SQLRepository[T]) HardDeleteContext(ctx context.Context, id any) error {
+	return r.delete(ctx, id, true)
+}
+
+// Restore undoes the soft delete of the row whose ID matches id.
+func (r SQLRepository[T]) Restore(id any) error {
+	return r.RestoreContext(context.Background(), id)
+}
+
+// RestoreContext undoes the soft delete of the row whose ID matches id by setting the column
+// tagged softdelete to NULL. The model must have such a field.
+func (r SQLRepository[T]) RestoreContext(ctx context.Context, id any) error {
+	if r.softDelete == "" {
+		return fmt.Errorf("cannot restore rows without a field tagged \"softdelete\"")
+	}
+
+	keyValues, err := r.keyValues(id)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	templates, err := r.getTemplates(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := r.db.PreparexContext(ctx, templates.GetRestore())
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	exec, err := stmt.ExecContext(ctx, append([]any{nil}, keyValues...)...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := exec.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected != 1 {
+		return fmt.Errorf("%d rows affected by UPDATE statement", affected)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package dvbcrud
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+)
+
+type softDeleteTestUser struct {
+	ID        int64        `db:"id"`
+	Name      string       `db:"name"`
+	DeletedAt sql.NullTime `db:"deleted_at,softdelete"`
+}
+
+type softDeleteTestTwice struct {
+	ID        int64        `db:"id"`
+	DeletedAt sql.NullTime `db:"deleted_at,softdelete"`
+	RemovedAt sql.NullTime `db:"removed_at,softdelete"`
+}
+
+func TestNew_SoftDeleteTaggedTwice(t *testing.T) {
+	mockDB, _, _ := sqlmock.New()
+	defer mockDB.Close()
+	expected := "softDeleteTestTwice has more than one field tagged \"softdelete\""
+
+	_, actual := New[softDeleteTestTwice](sqlx.NewDb(mockDB, "sqlmock"), WithDialect(PostgreSQL), WithTable("users"))
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_Read_SoftDelete(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("SELECT id, name, deleted_at FROM users WHERE id = $1 AND deleted_at IS NULL").
+		ExpectQuery().
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow(1, "AnyName", nil))
+
+	actual, err := repo.Read(1)
+
+	if err != nil || actual.ID != 1 {
+		t.Fatalf("Expected user 1 but got %v (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_ReadAll_SoftDelete(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("SELECT id, name, deleted_at FROM users WHERE deleted_at IS NULL").
+		ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow(1, "AnyName", nil))
+
+	actual, err := repo.ReadAll()
+
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 user but got %v (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_ReadWhere_SoftDelete(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("SELECT id, name, deleted_at FROM users WHERE (name = $1) AND (deleted_at IS NULL)").
+		ExpectQuery().
+		WithArgs("AnyName").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}))
+
+	_, err := repo.ReadWhere(Eq("name", "AnyName"))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestSqlRepository_ReadWithDeleted(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("SELECT id, name, deleted_at FROM users WHERE id = $1").
+		ExpectQuery().
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow(1, "AnyName", nil))
+
+	actual, err := repo.ReadWithDeleted(1)
+
+	if err != nil || actual.ID != 1 {
+		t.Fatalf("Expected user 1 but got %v (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_Delete_SoftDelete(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE users SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL").
+		ExpectExec().
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Delete(1)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSqlRepository_DeleteWhere_SoftDelete(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE users SET deleted_at = $1 WHERE (name = $2) AND (deleted_at IS NULL)").
+		ExpectExec().
+		WithArgs(sqlmock.AnyArg(), "AnyName").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	actual, err := repo.DeleteWhere(Eq("name", "AnyName"))
+
+	if err != nil || actual != 2 {
+		t.Fatalf("Expected 2 rows to be deleted but got %d (%v)", actual, err)
+	}
+}
+
+func TestSqlRepository_HardDelete(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("DELETE FROM users WHERE id = $1").
+		ExpectExec().
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.HardDelete(1)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSqlRepository_Update_SoftDeleted(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+	expected := "0 rows affected by UPDATE statement"
+
+	mock.ExpectPrepare("UPDATE users SET name = $1 WHERE id = $2 AND deleted_at IS NULL").
+		ExpectExec().
+		WithArgs("AnyName", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	actual := repo.Update(1, &softDeleteTestUser{Name: "AnyName"})
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSqlRepository_Patch_SoftDeleteColumn(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+	expected := "cannot update soft delete column \"deleted_at\", use Delete or Restore"
+
+	actual := repo.Patch(1, map[string]any{"deleted_at": nil})
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSqlRepository_Restore(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[softDeleteTestUser](PostgreSQL, WithTable("users"))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE users SET deleted_at = $1 WHERE id = $2").
+		ExpectExec().
+		WithArgs(nil, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Restore(1)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestSqlRepository_Restore_NoSoftDelete(t *testing.T) {
+	repo, mockDB, _, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	expected := "cannot restore rows without a field tagged \"softdelete\""
+
+	actual := repo.Restore(1)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
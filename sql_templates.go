@@ -1,76 +1,408 @@
 package dvbcrud
 
+import (
+	"fmt"
+	"sync"
+)
+
 type sqlTemplates interface {
-	// GetSelect returns the SELECT statement (WHERE ID)
+	// GetSelect returns the SELECT statement (WHERE ID), which leaves out soft-deleted rows
 	GetSelect() string
 
-	// GetSelectAll returns the SELECT statement (all rows)
+	// GetSelectWithDeleted returns the SELECT statement (WHERE ID), including soft-deleted rows
+	GetSelectWithDeleted() string
+
+	// GetSelectAll returns the SELECT statement (all rows), which leaves out soft-deleted rows
 	GetSelectAll() string
 
+	// GetSelectWhere generates and returns a SELECT statement for the rows matching filter,
+	// along with its arguments
+	GetSelectWhere(filter Filter) (string, []any, error)
+
+	// GetSelectForUpdate generates and returns a SELECT statement that locks the rows matching filter,
+	// along with its arguments. The rows are sorted by orderBy unless it is empty, and limited to
+	// limit rows unless it is 0.
+	GetSelectForUpdate(filter Filter, orderBy []OrderBy, limit int, lock rowLock) (string, []any, error)
+
+	// GetSelectFields generates and returns a SELECT statement of fields for the rows matching filter,
+	// along with its arguments
+	GetSelectFields(fields []string, filter Filter) (string, []any, error)
+
+	// GetSelectPage generates and returns a SELECT statement for a page of the rows matching filter,
+	// along with its arguments. orderBy defaults to the ID columns.
+	GetSelectPage(filter Filter, orderBy []OrderBy, limit int, offset int) (string, []any, error)
+
+	// GetAggregate generates and returns a statement that aggregates column with fn over the rows
+	// matching filter, grouped by groupBy unless it is empty, along with its arguments
+	GetAggregate(fn AggregateFunc, column string, groupBy string, filter Filter) (string, []any, error)
+
+	// GetCountWhere generates and returns a SELECT COUNT(*) statement for the rows matching filter,
+	// along with its arguments
+	GetCountWhere(filter Filter) (string, []any, error)
+
 	// GetInsert generates and returns an INSERT INTO statement
 	GetInsert(fields []string) (string, error)
 
-	// GetUpdate generates and returns an UPDATE statement
+	// GetInsertMany generates and returns an INSERT INTO statement for rows rows
+	GetInsertMany(fields []string, rows int) (string, error)
+
+	// GetInsertReturning generates and returns an INSERT INTO statement that returns all fields
+	GetInsertReturning(fields []string) (string, error)
+
+	// GetUpsert generates and returns an INSERT or UPDATE statement
+	GetUpsert(fields []string, conflictFields []string, updateFields []string) (string, error)
+
+	// GetUpdate generates and returns an UPDATE statement, which leaves out soft-deleted rows
 	GetUpdate(fields []string) (string, error)
 
+	// GetUpdateVersioned generates and returns an UPDATE statement that also requires versionField
+	// to match, whose placeholder comes after the ID placeholders
+	GetUpdateVersioned(fields []string, versionField string) (string, error)
+
+	// GetUpdateWhere generates and returns an UPDATE statement of fields for the rows matching filter,
+	// along with the arguments of the filter
+	GetUpdateWhere(fields []string, filter Filter) (string, []any, error)
+
+	// GetClaim generates and returns an UPDATE ... RETURNING statement that claims at most limit of
+	// the rows matching filter for a Queue, along with the arguments of the filter
+	GetClaim(columns queueColumns, filter Filter, limit int) (string, []any, error)
+
 	// GetDelete returns the DELETE statement
 	GetDelete() string
+
+	// GetSoftDelete returns the UPDATE statement that sets the soft delete column (WHERE ID),
+	// or an empty string if the table has none
+	GetSoftDelete() string
+
+	// GetRestore returns the UPDATE statement that sets the soft delete column (WHERE ID) of any row,
+	// or an empty string if the table has none
+	GetRestore() string
+
+	// GetDeleteWhere generates and returns a DELETE statement for the rows matching filter,
+	// along with its arguments
+	GetDeleteWhere(filter Filter) (string, []any, error)
 }
 
 type sqlTemplatesImpl struct {
 	sqlTemplates
-	sqlGen    sqlGenerator
-	tableName string
-	idField   string
+	sqlGen          sqlGenerator
+	tableName       string
+	idFields        []string
+	allFields       []string
+	softDeleteField string
+	versionField    string
 
-	selectSql    string
-	selectAllSql string
-	deleteSql    string
+	selectSql            string
+	selectWithDeletedSql string
+	selectAllSql         string
+	deleteSql            string
+	softDeleteSql        string
+	restoreSql           string
+	notDeletedSql        string
+}
+
+// notDeleted narrows filter to the rows that are not soft-deleted, if the table has a soft delete column.
+func (s sqlTemplatesImpl) notDeleted(filter Filter) Filter {
+	if s.softDeleteField == "" {
+		return filter
+	}
+	return And(filter, IsNull(s.softDeleteField))
 }
 
 func (s sqlTemplatesImpl) GetSelect() string {
 	return s.selectSql
 }
 
+func (s sqlTemplatesImpl) GetSelectWithDeleted() string {
+	return s.selectWithDeletedSql
+}
+
 func (s sqlTemplatesImpl) GetSelectAll() string {
 	return s.selectAllSql
 }
 
+func (s sqlTemplatesImpl) GetSelectWhere(filter Filter) (string, []any, error) {
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.sqlGen.GenerateSelectWhere(s.tableName, s.allFields, where), args, nil
+}
+
+func (s sqlTemplatesImpl) GetSelectForUpdate(filter Filter, orderBy []OrderBy, limit int, lock rowLock) (string, []any, error) {
+	var order string
+	if len(orderBy) > 0 {
+		var err error
+		order, err = s.sqlGen.GenerateOrderBy(orderBy, s.allFields)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, err := s.sqlGen.GenerateSelectForUpdate(s.tableName, s.allFields, where, order, limit, lock)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+func (s sqlTemplatesImpl) GetSelectFields(fields []string, filter Filter) (string, []any, error) {
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.sqlGen.GenerateSelectWhere(s.tableName, fields, where), args, nil
+}
+
+func (s sqlTemplatesImpl) GetSelectPage(filter Filter, orderBy []OrderBy, limit int, offset int) (string, []any, error) {
+	if len(orderBy) == 0 {
+		for _, idField := range s.idFields {
+			orderBy = append(orderBy, Asc(idField))
+		}
+	}
+
+	order, err := s.sqlGen.GenerateOrderBy(orderBy, s.allFields)
+	if err != nil {
+		return "", nil, err
+	}
+
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, err := s.sqlGen.GenerateSelectPage(s.tableName, s.allFields, where, order, limit, offset)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+func (s sqlTemplatesImpl) GetAggregate(fn AggregateFunc, column string, groupBy string, filter Filter) (string, []any, error) {
+	for _, name := range []string{column, groupBy} {
+		if name != "" && !contains(s.allFields, name) {
+			return "", nil, fmt.Errorf("unknown column \"%s\" in aggregate", name)
+		}
+	}
+
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, err := s.sqlGen.GenerateAggregate(s.tableName, fn, column, groupBy, where)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+func (s sqlTemplatesImpl) GetCountWhere(filter Filter) (string, []any, error) {
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.sqlGen.GenerateCount(s.tableName, where), args, nil
+}
+
 func (s sqlTemplatesImpl) GetInsert(fields []string) (string, error) {
 	return s.sqlGen.GenerateInsert(s.tableName, fields)
 }
 
+func (s sqlTemplatesImpl) GetInsertMany(fields []string, rows int) (string, error) {
+	return s.sqlGen.GenerateInsertMany(s.tableName, fields, rows)
+}
+
+func (s sqlTemplatesImpl) GetInsertReturning(fields []string) (string, error) {
+	return s.sqlGen.GenerateInsertReturning(s.tableName, s.idFields[0], fields, s.allFields)
+}
+
+func (s sqlTemplatesImpl) GetUpsert(fields []string, conflictFields []string, updateFields []string) (string, error) {
+	return s.sqlGen.GenerateUpsert(s.tableName, fields, conflictFields, updateFields, s.increments())
+}
+
 func (s sqlTemplatesImpl) GetUpdate(fields []string) (string, error) {
-	return s.sqlGen.GenerateUpdate(s.tableName, s.idField, fields)
+	return s.withNotDeleted(s.sqlGen.GenerateUpdate(s.tableName, s.idFields, fields, s.increments()))
+}
+
+// increments returns the columns that every UPDATE increments, i.e. the version column if the table has one.
+func (s sqlTemplatesImpl) increments() []string {
+	return versionIncrements(s.versionField)
+}
+
+// versionIncrements returns versionField as the only column to increment, or nil if it is empty.
+func versionIncrements(versionField string) []string {
+	if versionField == "" {
+		return nil
+	}
+	return []string{versionField}
+}
+
+// withNotDeleted appends the condition that leaves out soft-deleted rows to the statement sql,
+// if the table has a soft delete column.
+func (s sqlTemplatesImpl) withNotDeleted(sql string, err error) (string, error) {
+	if err != nil || s.notDeletedSql == "" {
+		return sql, err
+	}
+	return sql + " AND " + s.notDeletedSql, nil
+}
+
+func (s sqlTemplatesImpl) GetUpdateVersioned(fields []string, versionField string) (string, error) {
+	keyFields := append(append([]string{}, s.idFields...), versionField)
+	return s.withNotDeleted(s.sqlGen.GenerateUpdate(s.tableName, keyFields, fields, s.increments()))
+}
+
+func (s sqlTemplatesImpl) GetUpdateWhere(fields []string, filter Filter) (string, []any, error) {
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, len(fields))
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, err := s.sqlGen.GenerateUpdateWhere(s.tableName, fields, s.increments(), where)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+func (s sqlTemplatesImpl) GetClaim(columns queueColumns, filter Filter, limit int) (string, []any, error) {
+	orderBy := make([]OrderBy, len(s.idFields))
+	for i, idField := range s.idFields {
+		orderBy[i] = Asc(idField)
+	}
+
+	order, err := s.sqlGen.GenerateOrderBy(orderBy, s.allFields)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The status and visible at values come first
+	where, args, err := s.sqlGen.GenerateWhere(s.notDeleted(filter), s.allFields, 2)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, err := s.sqlGen.GenerateClaim(s.tableName, s.idFields, s.allFields, s.increments(), columns, where, order, limit)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
 }
 
 func (s sqlTemplatesImpl) GetDelete() string {
 	return s.deleteSql
 }
 
+func (s sqlTemplatesImpl) GetSoftDelete() string {
+	return s.softDeleteSql
+}
+
+func (s sqlTemplatesImpl) GetRestore() string {
+	return s.restoreSql
+}
+
+func (s sqlTemplatesImpl) GetDeleteWhere(filter Filter) (string, []any, error) {
+	where, args, err := s.sqlGen.GenerateWhere(filter, s.allFields, 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, err := s.sqlGen.GenerateDeleteWhere(s.tableName, where)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
 // newSQLTemplates pre-generates the SELECT, SELECT ALL and DELETE statement and returns a struct containing the templates.
-func newSQLTemplates(sqlGen sqlGenerator, tableName string, idField string, allFields []string) (sqlTemplates, error) {
-	selectSql, err := sqlGen.GenerateSelect(tableName, idField, allFields)
+// With a softDeleteField, the SELECT statements leave out soft-deleted rows and the soft delete statement is generated too.
+// With a versionField, every UPDATE statement increments it.
+func newSQLTemplates(sqlGen sqlGenerator, tableName string, idFields []string, allFields []string, softDeleteField string, versionField string) (sqlTemplates, error) {
+	selectSql, err := sqlGen.GenerateSelect(tableName, idFields, allFields)
 	if err != nil {
 		return nil, err
 	}
 
+	selectWithDeletedSql := selectSql
 	selectAllSql := sqlGen.GenerateSelectAll(tableName, allFields)
 
-	deleteSql, err := sqlGen.GenerateDelete(tableName, idField)
+	deleteSql, err := sqlGen.GenerateDelete(tableName, idFields)
 	if err != nil {
 		return nil, err
 	}
 
+	var softDeleteSql, restoreSql, notDeleted string
+	if softDeleteField != "" {
+		notDeleted, _, err = sqlGen.GenerateWhere(IsNull(softDeleteField), allFields, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		selectSql += " AND " + notDeleted
+		selectAllSql = sqlGen.GenerateSelectWhere(tableName, allFields, notDeleted)
+
+		restoreSql, err = sqlGen.GenerateUpdate(tableName, idFields, []string{softDeleteField}, versionIncrements(versionField))
+		if err != nil {
+			return nil, err
+		}
+
+		// Deleting a soft-deleted row again keeps the original timestamp
+		softDeleteSql = restoreSql + " AND " + notDeleted
+	}
+
 	sqlTemp := sqlTemplatesImpl{
-		sqlGen:    sqlGen,
-		tableName: tableName,
-		idField:   idField,
+		sqlGen:          sqlGen,
+		tableName:       tableName,
+		idFields:        idFields,
+		allFields:       allFields,
+		softDeleteField: softDeleteField,
+		versionField:    versionField,
 	}
 
 	sqlTemp.selectSql = selectSql
+	sqlTemp.selectWithDeletedSql = selectWithDeletedSql
 	sqlTemp.selectAllSql = selectAllSql
 	sqlTemp.deleteSql = deleteSql
+	sqlTemp.softDeleteSql = softDeleteSql
+	sqlTemp.restoreSql = restoreSql
+	sqlTemp.notDeletedSql = notDeleted
 
 	return &sqlTemp, nil
 }
+
+// sqlTemplatesCache holds templates per table name, generating them on first use.
+type sqlTemplatesCache struct {
+	mu        sync.Mutex
+	templates map[string]sqlTemplates
+	generate  func(tableName string) (sqlTemplates, error)
+}
+
+// Get returns the templates for tableName, generating and caching them if needed.
+func (c *sqlTemplatesCache) Get(tableName string) (sqlTemplates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if templates, ok := c.templates[tableName]; ok {
+		return templates, nil
+	}
+
+	templates, err := c.generate(tableName)
+	if err != nil {
+		return nil, err
+	}
+	c.templates[tableName] = templates
+
+	return templates, nil
+}
+
+func newSQLTemplatesCache(generate func(tableName string) (sqlTemplates, error)) *sqlTemplatesCache {
+	return &sqlTemplatesCache{
+		templates: map[string]sqlTemplates{},
+		generate:  generate,
+	}
+}
@@ -49,10 +49,33 @@ func TestSqlTemplatesImpl_GetInsert(t *testing.T) {
 	}
 }
 
+func TestSqlTemplatesImpl_GetInsertReturning(t *testing.T) {
+	expected := "AnyInsertReturningStatement"
+	sqlGenMock := sqlGeneratorMock{
+		generateInsertRetMock: func(table string, idField string, fields []string, returnFields []string) (string, error) {
+			if len(returnFields) != 3 {
+				t.Fatalf("Expected all fields to be returned but got %v", returnFields)
+			}
+			return expected, nil
+		},
+	}
+	templates := sqlTemplatesImpl{
+		sqlGen:    sqlGenMock,
+		idFields:  []string{"id_col"},
+		allFields: []string{"id_col", "col_1", "col_2"},
+	}
+
+	actual, _ := templates.GetInsertReturning([]string{"col_1", "col_2"})
+
+	if actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\"", expected, actual)
+	}
+}
+
 func TestSqlTemplatesImpl_GetUpdate(t *testing.T) {
 	expected := "AnyUpdateStatement"
 	sqlGenMock := sqlGeneratorMock{
-		generateUpdateMock: func(table string, idField string, fields []string) (string, error) {
+		generateUpdateMock: func(table string, idFields []string, fields []string, increments []string) (string, error) {
 			return expected, nil
 		},
 	}
@@ -82,13 +105,13 @@ func TestSqlTemplatesImpl_GetDelete(t *testing.T) {
 
 func TestNewSQLTemplates(t *testing.T) {
 	sqlGenMock := sqlGeneratorMock{
-		generateSelectMock: func(table string, idField string, fields []string) (string, error) {
+		generateSelectMock: func(table string, idFields []string, fields []string) (string, error) {
 			return "AnySelect", nil
 		},
 		generateSelectAllMock: func(table string, fields []string) string {
 			return "AnySelectAll"
 		},
-		generateDeleteMock: func(table string, idField string) (string, error) {
+		generateDeleteMock: func(table string, idFields []string) (string, error) {
 			return "AnyDelete", nil
 		},
 	}
@@ -96,13 +119,13 @@ func TestNewSQLTemplates(t *testing.T) {
 	expected := sqlTemplatesImpl{
 		sqlGen:       sqlGenMock,
 		tableName:    "any_table",
-		idField:      "id_col",
+		idFields:     []string{"id_col"},
 		selectSql:    "AnySelect",
 		selectAllSql: "AnySelectAll",
 		deleteSql:    "AnyDelete",
 	}
 
-	actual, _ := newSQLTemplates(sqlGenMock, "any_table", "id_col", []string{"id_col", "col_1", "col_2"})
+	actual, _ := newSQLTemplates(sqlGenMock, "any_table", []string{"id_col"}, []string{"id_col", "col_1", "col_2"}, "", "")
 
 	if expected.GetSelect() != actual.GetSelect() ||
 		expected.GetSelectAll() != actual.GetSelectAll() ||
@@ -114,12 +137,12 @@ func TestNewSQLTemplates(t *testing.T) {
 func TestNewSQLTemplates_GenerateSelectErr(t *testing.T) {
 	expected := fmt.Errorf("AnyError")
 	sqlGenMock := sqlGeneratorMock{
-		generateSelectMock: func(table string, idField string, fields []string) (string, error) {
+		generateSelectMock: func(table string, idFields []string, fields []string) (string, error) {
 			return "", expected
 		},
 	}
 
-	_, actual := newSQLTemplates(sqlGenMock, "", "", []string{})
+	_, actual := newSQLTemplates(sqlGenMock, "", []string{""}, []string{}, "", "")
 
 	if actual != expected {
 		t.Fatalf("Expected %v but got %v", expected, actual)
@@ -129,20 +152,121 @@ func TestNewSQLTemplates_GenerateSelectErr(t *testing.T) {
 func TestNewSQLTemplates_GenerateDeleteErr(t *testing.T) {
 	expected := fmt.Errorf("AnyError")
 	sqlGenMock := sqlGeneratorMock{
-		generateSelectMock: func(table string, idField string, fields []string) (string, error) {
+		generateSelectMock: func(table string, idFields []string, fields []string) (string, error) {
 			return "", nil
 		},
 		generateSelectAllMock: func(table string, fields []string) string {
 			return ""
 		},
-		generateDeleteMock: func(table string, idField string) (string, error) {
+		generateDeleteMock: func(table string, idFields []string) (string, error) {
 			return "", expected
 		},
 	}
 
-	_, actual := newSQLTemplates(sqlGenMock, "", "", []string{})
+	_, actual := newSQLTemplates(sqlGenMock, "", []string{""}, []string{}, "", "")
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSqlTemplatesCache_Get(t *testing.T) {
+	generated := 0
+	cache := newSQLTemplatesCache(func(tableName string) (sqlTemplates, error) {
+		generated++
+		return &sqlTemplatesImpl{tableName: tableName}, nil
+	})
+
+	first, _ := cache.Get("events_2026_10")
+	second, _ := cache.Get("events_2026_10")
+	other, _ := cache.Get("events_2026_11")
+
+	if generated != 2 {
+		t.Fatalf("Expected templates to be generated 2 times but got %d", generated)
+	}
+	if first != second {
+		t.Fatalf("Expected cached templates to be reused")
+	}
+	if other.(*sqlTemplatesImpl).tableName != "events_2026_11" {
+		t.Fatalf("Expected templates for events_2026_11 but got %v", other)
+	}
+}
+
+func TestSqlTemplatesCache_Get_GenerateErr(t *testing.T) {
+	expected := fmt.Errorf("AnyError")
+	cache := newSQLTemplatesCache(func(tableName string) (sqlTemplates, error) {
+		return nil, expected
+	})
+
+	_, actual := cache.Get("any_table")
 
 	if actual != expected {
 		t.Fatalf("Expected %v but got %v", expected, actual)
 	}
+	if len(cache.templates) != 0 {
+		t.Fatalf("Expected failed templates not to be cached")
+	}
+}
+
+func TestSqlTemplatesImpl_GetSelectWhere(t *testing.T) {
+	sqlGen := newSQLGenerator(newSQLParamGen(PostgreSQL), nil, PostgreSQL)
+	templates, _ := newSQLTemplates(sqlGen, "any_table", []string{"id"}, []string{"id", "name"}, "", "")
+
+	expected := "SELECT id, name FROM any_table WHERE (id > $1) AND (name = $2)"
+	actual, args, err := templates.GetSelectWhere(And(Gt("id", 1), Eq("name", "Ann")))
+
+	if err != nil || actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", expected, actual, err)
+	}
+	if len(args) != 2 || args[0] != 1 || args[1] != "Ann" {
+		t.Fatalf("Expected args [1 Ann] but got %v", args)
+	}
+}
+
+func TestSqlTemplatesImpl_GetDeleteWhere_UnknownColumn(t *testing.T) {
+	sqlGen := newSQLGenerator(newSQLParamGen(PostgreSQL), nil, PostgreSQL)
+	templates, _ := newSQLTemplates(sqlGen, "any_table", []string{"id"}, []string{"id", "name"}, "", "")
+	expected := "unknown column \"surname\" in filter"
+
+	_, _, actual := templates.GetDeleteWhere(Eq("surname", "Doe"))
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlTemplatesImpl_GetSelectPage_DefaultOrder(t *testing.T) {
+	sqlGen := newSQLGenerator(newSQLParamGen(PostgreSQL), nil, PostgreSQL)
+	templates, _ := newSQLTemplates(sqlGen, "any_table", []string{"id"}, []string{"id", "name"}, "", "")
+
+	expected := "SELECT id, name FROM any_table ORDER BY id LIMIT 10 OFFSET 0"
+	actual, _, err := templates.GetSelectPage(nil, nil, 10, 0)
+
+	if err != nil || actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", expected, actual, err)
+	}
+}
+
+func TestSqlTemplatesImpl_GetSelectFields(t *testing.T) {
+	sqlGen := newSQLGenerator(newSQLParamGen(SQLServer), nil, SQLServer)
+	templates, _ := newSQLTemplates(sqlGen, "any_table", []string{"id"}, []string{"id", "name", "email"}, "", "")
+
+	expected := "SELECT id, name FROM any_table WHERE email IS NOT NULL"
+	actual, _, err := templates.GetSelectFields([]string{"id", "name"}, IsNotNull("email"))
+
+	if err != nil || actual != expected {
+		t.Fatalf("Expected \"%s\" but got \"%s\" (%v)", expected, actual, err)
+	}
+}
+
+func TestSqlTemplatesImpl_GetAggregate_UnknownColumn(t *testing.T) {
+	sqlGen := newSQLGenerator(newSQLParamGen(MySQL), nil, MySQL)
+	templates, _ := newSQLTemplates(sqlGen, "any_table", []string{"id"}, []string{"id", "amount"}, "", "")
+	expected := "unknown column \"status\" in aggregate"
+
+	_, _, actual := templates.GetAggregate(AggregateSum, "amount", "status", nil)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
 }
@@ -0,0 +1,161 @@
+package dvbcrud
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"sync/atomic"
+)
+
+// ErrSavepointsUnsupported is returned by nested RunInTx calls when the dialect lacks savepoints.
+var ErrSavepointsUnsupported = errors.New("dialect does not support savepoints")
+
+// savepointCounter numbers savepoints so that nested savepoints get unique names.
+var savepointCounter uint64
+
+// Executor runs statements against a database. *sqlx.DB, *sqlx.Tx and *sqlx.Conn all satisfy it.
+type Executor interface {
+	PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+var (
+	_ Executor = (*sqlx.DB)(nil)
+	_ Executor = (*sqlx.Tx)(nil)
+	_ Executor = (*sqlx.Conn)(nil)
+)
+
+// txBeginner is an Executor that can begin transactions, i.e. *sqlx.DB and *sqlx.Conn.
+type txBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// RunInTx begins a transaction on db and passes it to fn. The transaction is committed
+// if fn returns nil, and rolled back if fn returns an error or panics.
+// Panics are re-raised after the rollback.
+//
+// If db is a *sqlx.Tx, i.e. RunInTx is nested, a savepoint is created instead. The savepoint
+// is released if fn returns nil and rolled back to otherwise, leaving the outer transaction intact.
+// The savepoint syntax is taken from the dialect registered for tx.DriverName().
+func RunInTx(ctx context.Context, db Executor, fn func(tx *sqlx.Tx) error) error {
+	return RunInTxWithDialect(ctx, db, nil, fn)
+}
+
+// RunInTxWithDialect works like RunInTx, but takes the savepoint syntax of nested calls from dialect,
+// e.g. the one a repository was given with WithDialect. A nil dialect is detected from tx.DriverName().
+func RunInTxWithDialect(ctx context.Context, db Executor, dialect Dialect, fn func(tx *sqlx.Tx) error) error {
+	if tx, ok := db.(*sqlx.Tx); ok {
+		return runInSavepoint(ctx, tx, dialect, fn)
+	}
+
+	beginner, ok := db.(txBeginner)
+	if !ok {
+		return fmt.Errorf("cannot begin a transaction on %T", db)
+	}
+
+	tx, err := beginner.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	return runAndCommit(tx, fn)
+}
+
+// runAndCommit calls fn with tx, then commits or rolls back tx depending on the outcome.
+func runAndCommit(tx *sqlx.Tx, fn func(tx *sqlx.Tx) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// savepointStatements holds the statements for creating, releasing and rolling back to a savepoint.
+// release is empty for dialects that cannot release savepoints.
+type savepointStatements struct {
+	create   string
+	release  string
+	rollback string
+}
+
+func newSavepointStatements(dialect Dialect, name string) (savepointStatements, error) {
+	switch dialect.Savepoints() {
+	case SavepointStandard:
+		return savepointStatements{
+			create:   "SAVEPOINT " + name,
+			release:  "RELEASE SAVEPOINT " + name,
+			rollback: "ROLLBACK TO SAVEPOINT " + name,
+		}, nil
+
+	case SavepointWithoutRelease:
+		return savepointStatements{
+			create:   "SAVEPOINT " + name,
+			rollback: "ROLLBACK TO SAVEPOINT " + name,
+		}, nil
+
+	case SavepointTransaction:
+		return savepointStatements{
+			create:   "SAVE TRANSACTION " + name,
+			rollback: "ROLLBACK TRANSACTION " + name,
+		}, nil
+
+	default:
+		return savepointStatements{}, fmt.Errorf("%w: %s", ErrSavepointsUnsupported, dialect.Name())
+	}
+}
+
+// runInSavepoint calls fn within a new savepoint in tx, using the savepoint syntax of dialect.
+// A nil dialect is detected from tx.DriverName().
+func runInSavepoint(ctx context.Context, tx *sqlx.Tx, dialect Dialect, fn func(tx *sqlx.Tx) error) (err error) {
+	if dialect == nil {
+		if dialect, err = DetectDialect(tx.DriverName()); err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("dvbcrud_sp%d", atomic.AddUint64(&savepointCounter, 1))
+	statements, err := newSavepointStatements(dialect, name)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, statements.create); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = tx.ExecContext(ctx, statements.rollback)
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, statements.rollback); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback to savepoint failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if statements.release != "" {
+		if _, err = tx.ExecContext(ctx, statements.release); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package dvbcrud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+)
+
+func TestRunInTx_Commit(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
+	mock.ExpectBegin()
+	mock.ExpectExec("AnyExec").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		_, err := tx.Exec("AnyExec")
+		return err
+	})
+
+	if err != nil {
+		t.Fatalf("Expected RunInTx to succeed, but got: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %s", err)
+	}
+}
+
+func TestRunInTx_RollbackOnErr(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
+	expected := fmt.Errorf("AnyError")
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	actual := RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		return expected
+	})
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %s", err)
+	}
+}
+
+func TestRunInTx_RollbackOnPanic(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	defer func() {
+		if p := recover(); p != "AnyPanic" {
+			t.Fatalf("Expected panic to be re-raised, but got %v", p)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("Unmet expectations: %s", err)
+		}
+	}()
+
+	_ = RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		panic("AnyPanic")
+	})
+}
+
+func TestRunInTx_BeginErr(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
+	expected := fmt.Errorf("AnyError")
+	mock.ExpectBegin().WillReturnError(expected)
+
+	actual := RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		t.Fatalf("Expected fn not to be called")
+		return nil
+	})
+
+	if actual != expected {
+		t.Fatalf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSqlRepository_WithTx(t *testing.T) {
+	repo, mockDB, mock, _ := newMock[repoTestUser]()
+	defer mockDB.Close()
+	repo.templates = sqlTemplatesMock{
+		GetDeleteMock: func() string {
+			return "AnyDelete"
+		},
+	}
+	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
+	mock.ExpectBegin()
+	mock.ExpectPrepare("AnyDelete").
+		ExpectExec().
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		txRepo := repo.WithTx(tx)
+		if txRepo.db != tx {
+			t.Fatalf("Expected repository copy to use tx")
+		}
+		return txRepo.Delete(1)
+	})
+
+	if err != nil {
+		t.Fatalf("Expected transaction to succeed, but got: %s", err)
+	}
+	if _, ok := repo.db.(*sqlx.DB); !ok {
+		t.Fatalf("Expected original repository to keep its executor")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %s", err)
+	}
+}
+
+func TestRunInTx_NestedReleasesSavepoint(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "postgres")
+	mock.ExpectBegin()
+	mock.ExpectExec("^SAVEPOINT dvbcrud_sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("AnyExec").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^RELEASE SAVEPOINT dvbcrud_sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		return RunInTx(context.Background(), tx, func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("AnyExec")
+			return err
+		})
+	})
+
+	if err != nil {
+		t.Fatalf("Expected RunInTx to succeed, but got: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %s", err)
+	}
+}
+
+func TestRunInTx_NestedErrRollsBackToSavepoint(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "postgres")
+	expected := fmt.Errorf("AnyError")
+	mock.ExpectBegin()
+	mock.ExpectExec("^SAVEPOINT dvbcrud_sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^ROLLBACK TO SAVEPOINT dvbcrud_sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		actual := RunInTx(context.Background(), tx, func(tx *sqlx.Tx) error {
+			return expected
+		})
+		if actual != expected {
+			t.Fatalf("Expected %v but got %v", expected, actual)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected outer transaction to commit, but got: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %s", err)
+	}
+}
+
+func TestRunInTxWithDialect_Nested(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "sqlmock")
+	mock.ExpectBegin()
+	mock.ExpectExec("^SAVE TRANSACTION dvbcrud_sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("AnyExec").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := RunInTxWithDialect(context.Background(), sqlxDb, SQLServer, func(tx *sqlx.Tx) error {
+		return RunInTxWithDialect(context.Background(), tx, SQLServer, func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("AnyExec")
+			return err
+		})
+	})
+
+	if err != nil {
+		t.Fatalf("Expected RunInTxWithDialect to succeed, but got: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %s", err)
+	}
+}
+
+func TestRunInTx_NestedUnsupported(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New()
+	defer mockDB.Close()
+	sqlxDb := sqlx.NewDb(mockDB, "odbc")
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err := RunInTx(context.Background(), sqlxDb, func(tx *sqlx.Tx) error {
+		return RunInTx(context.Background(), tx, func(tx *sqlx.Tx) error {
+			t.Fatalf("Expected fn not to be called")
+			return nil
+		})
+	})
+
+	if !errors.Is(err, ErrSavepointsUnsupported) {
+		t.Fatalf("Expected ErrSavepointsUnsupported but got %v", err)
+	}
+}
+
+func TestNewSavepointStatements(t *testing.T) {
+	tests := map[Dialect]savepointStatements{
+		MySQL: {
+			create:   "SAVEPOINT sp",
+			release:  "RELEASE SAVEPOINT sp",
+			rollback: "ROLLBACK TO SAVEPOINT sp",
+		},
+		Oracle: {
+			create:   "SAVEPOINT sp",
+			rollback: "ROLLBACK TO SAVEPOINT sp",
+		},
+		SQLServer: {
+			create:   "SAVE TRANSACTION sp",
+			rollback: "ROLLBACK TRANSACTION sp",
+		},
+	}
+
+	for dialect, expected := range tests {
+		actual, err := newSavepointStatements(dialect, "sp")
+		if err != nil {
+			t.Fatalf("Expected statements for %s, but got: %s", dialect.Name(), err)
+		}
+		if actual != expected {
+			t.Fatalf("Expected %v for %s but got %v", expected, dialect.Name(), actual)
+		}
+	}
+}
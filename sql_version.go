@@ -0,0 +1,90 @@
+package dvbcrud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrStaleObject is returned by Update and UpdateColumns when the row was changed or removed
+// since the model was read, i.e. its version no longer matches the version in the model.
+var ErrStaleObject = errors.New("row was changed or removed since it was read")
+
+// parseVersionField returns the name of the field of T tagged version, or an empty string if there is none.
+// The field must be an integer.
+func parseVersionField[T any](structParser StructParser) (string, error) {
+	var hack T
+	typ := reflect.TypeOf(hack)
+
+	fields, err := structParser.ParseTaggedFields(typ, "version")
+	if err != nil {
+		return "", err
+	}
+	if len(fields) == 0 {
+		return "", nil
+	}
+	if len(fields) > 1 {
+		return "", fmt.Errorf("%s has more than one field tagged \"version\"", typ.Name())
+	}
+
+	ptr, err := structParser.FieldPointer(&hack, fields[0])
+	if err != nil {
+		return "", err
+	}
+	if _, err := readInt64(ptr); err != nil {
+		return "", fmt.Errorf("field tagged \"version\": %w", err)
+	}
+
+	return fields[0], nil
+}
+
+// updateModel sets fields to values in the row whose ID matches id. If the model has a version column,
+// the version in model is checked, and then incremented in model like the UPDATE statement does in the row.
+func (r SQLRepository[T]) updateModel(ctx context.Context, id any, model *T, fields []string, values []any) error {
+	if r.version == "" {
+		return r.update(ctx, id, *model, fields, values, nil)
+	}
+
+	ptr, err := r.structParser.FieldPointer(model, r.version)
+	if err != nil {
+		return err
+	}
+	version, err := readInt64(ptr)
+	if err != nil {
+		return err
+	}
+
+	setFields := make([]string, 0, len(fields))
+	setValues := make([]any, 0, len(values))
+	for i, field := range fields {
+		if field != r.version {
+			setFields = append(setFields, field)
+			setValues = append(setValues, values[i])
+		}
+	}
+
+	if err := r.update(ctx, id, *model, setFields, setValues, &version); err != nil {
+		return err
+	}
+
+	return assignInt64(ptr, version+1)
+}
+
+// incrementVersion increments the version in model, after it was incremented in the row by an UPDATE statement
+// that did not check it. Models without a version column are left as they are.
+func (r SQLRepository[T]) incrementVersion(model *T) error {
+	if r.version == "" {
+		return nil
+	}
+
+	ptr, err := r.structParser.FieldPointer(model, r.version)
+	if err != nil {
+		return err
+	}
+	version, err := readInt64(ptr)
+	if err != nil {
+		return err
+	}
+	return assignInt64(ptr, version+1)
+}
@@ -0,0 +1,156 @@
+package dvbcrud
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"testing"
+)
+
+type versionTestDocument struct {
+	ID      int64  `db:"id"`
+	Title   string `db:"title"`
+	Version int    `db:"version,version"`
+}
+
+type versionTestNotInteger struct {
+	ID      int64  `db:"id"`
+	Version string `db:"version,version"`
+}
+
+func TestNew_VersionNotInteger(t *testing.T) {
+	mockDB, _, _ := sqlmock.New()
+	defer mockDB.Close()
+	expected := "field tagged \"version\": expected an integer but got string"
+
+	_, actual := New[versionTestNotInteger](sqlx.NewDb(mockDB, "sqlmock"), WithDialect(PostgreSQL), WithTable("documents"))
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_Update_Version(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE documents SET title = $1, version = version + 1 WHERE id = $2 AND version = $3").
+		ExpectExec().
+		WithArgs("AnyTitle", 1, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	document := versionTestDocument{ID: 1, Title: "AnyTitle", Version: 3}
+	err := repo.Update(1, &document)
+
+	if err != nil || document.Version != 4 {
+		t.Fatalf("Expected version 4 but got %d (%v)", document.Version, err)
+	}
+}
+
+func TestSqlRepository_Update_Stale(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE documents SET title = $1, version = version + 1 WHERE id = $2 AND version = $3").
+		ExpectExec().
+		WithArgs("AnyTitle", 1, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	document := versionTestDocument{ID: 1, Title: "AnyTitle", Version: 3}
+	actual := repo.Update(1, &document)
+
+	if actual != ErrStaleObject || document.Version != 3 {
+		t.Fatalf("Expected %v and version 3 but got %v and version %d", ErrStaleObject, actual, document.Version)
+	}
+}
+
+func TestSqlRepository_UpdateColumns_Version(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE documents SET title = $1, version = version + 1 WHERE id = $2 AND version = $3").
+		ExpectExec().
+		WithArgs("AnyTitle", 1, int64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	document := versionTestDocument{ID: 1, Title: "AnyTitle"}
+	err := repo.UpdateColumns(1, &document, "title")
+
+	if err != nil || document.Version != 1 {
+		t.Fatalf("Expected version 1 but got %d (%v)", document.Version, err)
+	}
+}
+
+func TestSqlRepository_Patch_VersionColumn(t *testing.T) {
+	repo, _, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+	expected := "cannot update version column \"version\""
+
+	actual := repo.Patch(1, map[string]any{"version": 5})
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_Upsert_Version(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+
+	mock.ExpectPrepare("INSERT INTO documents (id, title, version) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, version = documents.version + 1").
+		ExpectExec().
+		WithArgs(1, "AnyTitle", 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Upsert(versionTestDocument{ID: 1, Title: "AnyTitle"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSqlRepository_UpsertColumns_VersionColumn(t *testing.T) {
+	repo, _, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+	expected := "cannot update version column \"version\""
+
+	actual := repo.UpsertColumns(versionTestDocument{ID: 1}, nil, []string{"title", "version"})
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
+
+func TestSqlRepository_Patch_Version(t *testing.T) {
+	repo, mock, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+
+	mock.ExpectPrepare("UPDATE documents SET title = $1, version = version + 1 WHERE id = $2").
+		ExpectExec().
+		WithArgs("AnyTitle", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Patch(1, map[string]any{"title": "AnyTitle"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSqlRepository_Update_NilModel(t *testing.T) {
+	repo, _, closeDB := newDialectMock[versionTestDocument](PostgreSQL, WithTable("documents"))
+	defer closeDB()
+	expected := "model cannot be nil"
+
+	actual := repo.Update(1, nil)
+
+	if actual == nil || actual.Error() != expected {
+		t.Fatalf("Expected \"%s\" but got \"%v\"", expected, actual)
+	}
+}
@@ -3,12 +3,19 @@ package dvbcrud
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type StructParser interface {
-	// ParseFieldNames TODO: write docs
+	// ParseFieldNames reads the struct type typ and returns the names in its db tags.
+	// Every field must have a db tag holding a valid identifier, optionally followed by
+	// comma-separated options, e.g. `db:"status,status"`.
 	ParseFieldNames(typ reflect.Type) ([]string, error)
 
+	// ParseTaggedFields reads the struct type typ and returns the names of the fields
+	// whose db tag has option.
+	ParseTaggedFields(typ reflect.Type, option string) ([]string, error)
+
 	// ParseProperties reads the struct type T and returns its fields
 	// and values as two slices. The slices are guaranteed to match indices.
 	//
@@ -16,14 +23,24 @@ type StructParser interface {
 	// when formatting and preparing statements.
 	//
 	// Specifying idFieldName filters out that field in the resulting slices,
-	// which is necessary in INSERTS and UPDATES.
+	// which is necessary in INSERTS and UPDATES. An empty idFieldName keeps all fields.
 	ParseProperties(model any, idFieldName string) ([]string, []any, error)
+
+	// FieldPointer returns a pointer to the field in model whose db tag is fieldName.
+	// model must be a pointer to a struct.
+	FieldPointer(model any, fieldName string) (any, error)
 }
 
 type structParserImpl struct {
 	StructParser
 }
 
+// parseTag splits a db tag into the column name and its options.
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
 func (s structParserImpl) ParseFieldNames(typ reflect.Type) ([]string, error) {
 	if typ.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("type must be a kind of struct")
@@ -33,10 +50,13 @@ func (s structParserImpl) ParseFieldNames(typ reflect.Type) ([]string, error) {
 	fields := make([]string, numFields)
 
 	for i := 0; i < numFields; i++ {
-		name := typ.Field(i).Tag.Get("db")
+		name, _ := parseTag(typ.Field(i).Tag.Get("db"))
 		if name == "" {
 			return nil, fmt.Errorf("%s.%s lacks a db tag", typ.Name(), typ.Field(i).Name)
 		}
+		if err := validateIdentifier(name); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typ.Name(), typ.Field(i).Name, err)
+		}
 
 		fields[i] = name
 	}
@@ -44,6 +64,22 @@ func (s structParserImpl) ParseFieldNames(typ reflect.Type) ([]string, error) {
 	return fields, nil
 }
 
+func (s structParserImpl) ParseTaggedFields(typ reflect.Type, option string) ([]string, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type must be a kind of struct")
+	}
+
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		name, options := parseTag(typ.Field(i).Tag.Get("db"))
+		if contains(options, option) {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields, nil
+}
+
 func (s structParserImpl) ParseProperties(model any, idFieldName string) ([]string, []any, error) {
 	val := reflect.ValueOf(model)
 	if val.Kind() != reflect.Struct {
@@ -51,27 +87,44 @@ func (s structParserImpl) ParseProperties(model any, idFieldName string) ([]stri
 	}
 
 	numField := val.NumField()
-	fields := make([]string, numField-1)
-	values := make([]any, numField-1)
+	fields := make([]string, 0, numField)
+	values := make([]any, 0, numField)
 
-	index := 0
 	for i := 0; i < numField; i++ {
-		name := val.Type().Field(i).Tag.Get("db")
+		name, _ := parseTag(val.Type().Field(i).Tag.Get("db"))
 		if name == "" {
 			return nil, nil, fmt.Errorf("%s.%s lacks a db tag", val.Type().Name(), val.Type().Field(i).Name)
 		}
+		if err := validateIdentifier(name); err != nil {
+			return nil, nil, fmt.Errorf("%s.%s: %w", val.Type().Name(), val.Type().Field(i).Name, err)
+		}
 		if name == idFieldName {
 			continue
 		}
 
-		fields[index] = name
-		values[index] = val.Field(i).Interface()
-		index++
+		fields = append(fields, name)
+		values = append(values, val.Field(i).Interface())
 	}
 
 	return fields, values, nil
 }
 
+func (s structParserImpl) FieldPointer(model any, fieldName string) (any, error) {
+	ptr := reflect.ValueOf(model)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a pointer to a struct type")
+	}
+
+	val := ptr.Elem()
+	for i := 0; i < val.NumField(); i++ {
+		if name, _ := parseTag(val.Type().Field(i).Tag.Get("db")); name == fieldName {
+			return val.Field(i).Addr().Interface(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s has no field tagged \"%s\"", val.Type().Name(), fieldName)
+}
+
 func newStructParser() StructParser {
 	return &structParserImpl{}
 }
@@ -1,6 +1,7 @@
 package dvbcrud
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -21,6 +22,11 @@ type testMissingTagAddress struct {
 	City    string
 }
 
+type testInjectedTag struct {
+	ID   uint64 `db:"id"`
+	Name string `db:"name FROM Users; --"`
+}
+
 func TestStructParserImpl_ParseFieldNames(t *testing.T) {
 	parser := newStructParser()
 	expected := []string{"UserId", "Name", "Surname", "Birthdate", "CreatedAt"}
@@ -106,3 +112,115 @@ func TestStructParserImpl_ParseProperties_MissingTag(t *testing.T) {
 		t.Fatalf("Expected error \"%s\" but got \"%s\" instead", expected, err.Error())
 	}
 }
+
+func TestStructParserImpl_ParseFieldNames_InvalidTag(t *testing.T) {
+	parser := newStructParser()
+	_, err := parser.ParseFieldNames(reflect.TypeOf(testInjectedTag{}))
+
+	var identifierErr *InvalidIdentifierError
+	if !errors.As(err, &identifierErr) {
+		t.Fatalf("Expected InvalidIdentifierError but got %v", err)
+	}
+	expected := "testInjectedTag.Name: invalid identifier \"name FROM Users; --\": contains illegal character ' '"
+	if err.Error() != expected {
+		t.Fatalf("Expected error \"%s\" but got \"%s\" instead", expected, err.Error())
+	}
+}
+
+func TestStructParserImpl_ParseProperties_InvalidTag(t *testing.T) {
+	parser := newStructParser()
+	_, _, err := parser.ParseProperties(testInjectedTag{}, "id")
+
+	var identifierErr *InvalidIdentifierError
+	if !errors.As(err, &identifierErr) {
+		t.Fatalf("Expected InvalidIdentifierError but got %v", err)
+	}
+}
+
+func TestStructParserImpl_FieldPointer(t *testing.T) {
+	parser := newStructParser()
+	user := structTestUser{}
+
+	ptr, err := parser.FieldPointer(&user, "UserId")
+	if err != nil {
+		t.Fatalf("Expected a pointer, but got: %s", err)
+	}
+	*ptr.(*uint64) = 42
+
+	if user.ID != 42 {
+		t.Fatalf("Expected pointer to point at the UserId field")
+	}
+}
+
+func TestStructParserImpl_FieldPointer_NonPointer(t *testing.T) {
+	parser := newStructParser()
+	_, err := parser.FieldPointer(structTestUser{}, "UserId")
+	expected := "model must be a pointer to a struct type"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error \"%s\" but got \"%v\" instead", expected, err)
+	}
+}
+
+func TestStructParserImpl_FieldPointer_UnknownField(t *testing.T) {
+	parser := newStructParser()
+	_, err := parser.FieldPointer(&structTestUser{}, "AnyField")
+	expected := "structTestUser has no field tagged \"AnyField\""
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error \"%s\" but got \"%v\" instead", expected, err)
+	}
+}
+
+func TestStructParserImpl_ParseProperties_AllFields(t *testing.T) {
+	parser := newStructParser()
+	user := structTestUser{ID: 1, Name: "AnyName"}
+
+	expectedFields := []string{"UserId", "Name", "Surname", "Birthdate", "CreatedAt"}
+	actualFields, actualValues, _ := parser.ParseProperties(user, "")
+
+	if !reflect.DeepEqual(expectedFields, actualFields) {
+		t.Fatalf("Actual fields didn't match expected fields")
+	} else if actualValues[0] != user.ID {
+		t.Fatalf("Expected ID value to be included")
+	}
+}
+
+type structTestJob struct {
+	ID       uint64 `db:"id"`
+	Status   string `db:"status,status"`
+	Attempts int    `db:"attempts,attempts,other"`
+}
+
+func TestStructParserImpl_ParseFieldNames_TagOptions(t *testing.T) {
+	parser := newStructParser()
+	expected := []string{"id", "status", "attempts"}
+
+	actual, err := parser.ParseFieldNames(reflect.TypeOf(structTestJob{}))
+
+	if err != nil || !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v but got %v (%v)", expected, actual, err)
+	}
+}
+
+func TestStructParserImpl_ParseTaggedFields(t *testing.T) {
+	parser := newStructParser()
+	expected := []string{"attempts"}
+
+	actual, err := parser.ParseTaggedFields(reflect.TypeOf(structTestJob{}), "other")
+
+	if err != nil || !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v but got %v (%v)", expected, actual, err)
+	}
+}
+
+func TestStructParserImpl_FieldPointer_TagOptions(t *testing.T) {
+	parser := newStructParser()
+	job := structTestJob{Status: "AnyStatus"}
+
+	actual, err := parser.FieldPointer(&job, "status")
+
+	if err != nil || actual != &job.Status {
+		t.Fatalf("Expected a pointer to Status but got %v (%v)", actual, err)
+	}
+}